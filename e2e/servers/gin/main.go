@@ -1,23 +1,75 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"math/big"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
+	"sync"
 	"syscall"
 	"time"
 
-	"github.com/coinbase/x402/go/pkg/coinbasefacilitator"
+	"github.com/coinbase/x402/go/pkg/facilitatorclient"
 	x402gin "github.com/coinbase/x402/go/pkg/gin"
-	"github.com/coinbase/x402/go/pkg/types"
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
+
+	// Blank-imported so their init() registers the backend with
+	// facilitatorclient's provider registry; the active one is picked at
+	// runtime via FACILITATOR_PROVIDER.
+	_ "github.com/coinbase/x402/go/pkg/coinbasefacilitator"
+	_ "github.com/coinbase/x402/go/pkg/localfacilitator"
+	_ "github.com/coinbase/x402/go/pkg/vaultfacilitator"
 )
 
+// defaultShutdownGrace bounds how long graceful shutdown waits for
+// in-flight requests and settlements before giving up on them.
+const defaultShutdownGrace = 30 * time.Second
+
 var shutdownRequested bool
 
+// shutdownGrace reads SHUTDOWN_GRACE (e.g. "30s"), falling back to
+// defaultShutdownGrace when unset or unparsable.
+func shutdownGrace() time.Duration {
+	v := os.Getenv("SHUTDOWN_GRACE")
+	if v == "" {
+		return defaultShutdownGrace
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		fmt.Printf("Warning: invalid SHUTDOWN_GRACE %q, using default %s\n", v, defaultShutdownGrace)
+		return defaultShutdownGrace
+	}
+	return d
+}
+
+// replaySettlementRetries re-submits any settlements that were still
+// pending when the process last shut down, so a restart doesn't silently
+// lose them.
+func replaySettlementRetries(queue *x402gin.FileRetryQueue, provider facilitatorclient.FacilitatorProvider) {
+	entries, err := queue.Load()
+	if err != nil {
+		fmt.Printf("Warning: failed to read settlement retry queue: %v\n", err)
+		return
+	}
+	if len(entries) == 0 {
+		return
+	}
+	fmt.Printf("Replaying %d settlement(s) left pending by a previous shutdown\n", len(entries))
+	for _, entry := range entries {
+		payload := entry.PaymentPayload()
+		if _, err := provider.Settle(payload, entry.Requirements); err != nil {
+			fmt.Printf("Warning: failed to replay settlement for %s: %v\n", entry.Requirements.Resource, err)
+		}
+	}
+	if err := queue.Clear(); err != nil {
+		fmt.Printf("Warning: failed to clear settlement retry queue: %v\n", err)
+	}
+}
+
 func main() {
 	// Load .env file if it exists
 	if err := godotenv.Load(); err != nil {
@@ -25,7 +77,6 @@ func main() {
 	}
 
 	// Get configuration from environment
-	useCdpFacilitator := os.Getenv("USE_CDP_FACILITATOR") == "true"
 	network := os.Getenv("EVM_NETWORK")
 	if network == "" {
 		network = "bsc-mainnet"
@@ -36,26 +87,27 @@ func main() {
 		port = "4021"
 	}
 
-	// CDP facilitator configuration
-	cdpAPIKeyID := os.Getenv("CDP_API_KEY_ID")
-	cdpAPIKeySecret := os.Getenv("CDP_API_KEY_SECRET")
-
 	if address == "" {
 		fmt.Println("Error: Missing required environment variable ADDRESS")
 		os.Exit(1)
 	}
 
-	// Validate CDP configuration if using CDP facilitator
-	if useCdpFacilitator && (cdpAPIKeyID == "" || cdpAPIKeySecret == "") {
-		fmt.Println("Error: CDP facilitator enabled but missing CDP_API_KEY_ID or CDP_API_KEY_SECRET")
+	// Select the facilitator backend via FACILITATOR_PROVIDER
+	// (cdp|local|vault, or any name registered with facilitatorclient).
+	// Defaults to "local" when unset.
+	facilitatorProvider, err := facilitatorclient.NewProviderFromEnv()
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
 		os.Exit(1)
 	}
 
-	// Create facilitator config if using CDP
-	var facilitatorConfig *types.FacilitatorConfig
-	if useCdpFacilitator {
-		facilitatorConfig = coinbasefacilitator.CreateFacilitatorConfig(cdpAPIKeyID, cdpAPIKeySecret)
+	retryQueuePath := os.Getenv("SETTLEMENT_RETRY_QUEUE")
+	if retryQueuePath == "" {
+		retryQueuePath = "./.x402-settlement-retries.jsonl"
 	}
+	retryQueue := x402gin.NewFileRetryQueue(retryQueuePath)
+	replaySettlementRetries(retryQueue, facilitatorProvider)
+	settlementTracker := x402gin.NewSettlementTracker(retryQueue)
 
 	// Set Gin to release mode to reduce logs
 	gin.SetMode(gin.ReleaseMode)
@@ -67,10 +119,11 @@ func main() {
 		x402gin.PaymentMiddleware(
 			big.NewFloat(0.001), // $0.001 USD
 			address,
-			x402gin.WithFacilitatorConfig(facilitatorConfig),
+			x402gin.WithFacilitatorProvider(facilitatorProvider),
 			x402gin.WithDescription("Protected endpoint requiring payment"),
 			x402gin.WithResource("http://localhost:"+port+"/protected"),
 			x402gin.WithTestnet(network == "bsc-mainnet"),
+			x402gin.WithSettlementTracker(settlementTracker),
 		),
 		func(c *gin.Context) {
 			if shutdownRequested {
@@ -100,45 +153,114 @@ func main() {
 		})
 	})
 
+	grace := shutdownGrace()
+
+	var httpServer *http.Server  // set below for the plain-HTTP path only
+	var autoTLS *x402gin.AutoTLS // set below for the AutoTLS path only
+
+	// shutdownDone is closed once shutdown() has fully drained, so that
+	// main can block on it before returning. Without this, main would
+	// race shutdown(): ListenAndServe's blocking call returns as soon as
+	// httpServer.Shutdown stops accepting new connections, and main
+	// returning kills the shutdown() goroutine (and its in-flight
+	// settlementTracker.Drain) along with every other goroutine in the
+	// process.
+	shutdownDone := make(chan struct{})
+
+	var shutdownOnce sync.Once
+	shutdown := func() {
+		shutdownOnce.Do(func() {
+			defer close(shutdownDone)
+
+			shutdownRequested = true
+			fmt.Printf("Shutting down, draining in-flight requests and settlements (grace=%s)...\n", grace)
+
+			ctx, cancel := context.WithTimeout(context.Background(), grace)
+			defer cancel()
+
+			if httpServer != nil {
+				if err := httpServer.Shutdown(ctx); err != nil {
+					fmt.Printf("Warning: server did not stop accepting connections cleanly: %v\n", err)
+				}
+			}
+			if autoTLS != nil {
+				if err := autoTLS.Shutdown(ctx); err != nil {
+					fmt.Printf("Warning: TLS server did not stop accepting connections cleanly: %v\n", err)
+				}
+			}
+
+			if err := settlementTracker.Drain(ctx); err != nil {
+				fmt.Printf("Warning: %d settlement(s) still pending after the grace period; recorded to %s for replay on next boot\n", settlementTracker.Pending(), retryQueuePath)
+			}
+		})
+	}
+
 	// Graceful shutdown endpoint
 	r.POST("/close", func(c *gin.Context) {
-		shutdownRequested = true
-
 		c.JSON(http.StatusOK, gin.H{
 			"message":   "Server shutting down gracefully",
 			"timestamp": "2024-01-01T00:00:00Z",
 		})
-
-		// Schedule server shutdown after response
-		go func() {
-			time.Sleep(100 * time.Millisecond)
-			os.Exit(0)
-		}()
+		go shutdown()
 	})
 
-	// Set up graceful shutdown
+	// Set up graceful shutdown on SIGINT/SIGTERM
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 
 	go func() {
 		<-quit
-		fmt.Println("Received shutdown signal, exiting...")
-		os.Exit(0)
+		fmt.Println("Received shutdown signal...")
+		shutdown()
 	}()
 
 	fmt.Printf("Starting Gin server on port %s\n", port)
 	fmt.Printf("Server address: %s\n", address)
 	fmt.Printf("Network: %s\n", network)
-	fmt.Printf("Using CDP facilitator: %t\n", useCdpFacilitator)
+	fmt.Printf("Facilitator provider: %s\n", facilitatorProviderName())
+	fmt.Printf("Shutdown grace period: %s\n", grace)
 	fmt.Printf("Server listening on port %s\n", port)
 
-	server := &http.Server{
+	// When AUTO_TLS_DOMAINS is set, terminate TLS ourselves with
+	// on-demand Let's Encrypt certificates instead of serving plaintext
+	// HTTP; x402 endpoints are meant to be reachable (and paid for) from
+	// the public internet, so operators shouldn't have to front this with
+	// their own TLS terminator just to get started.
+	if domains := os.Getenv("AUTO_TLS_DOMAINS"); domains != "" {
+		cacheDir := os.Getenv("AUTO_TLS_CACHE_DIR")
+		if cacheDir == "" {
+			cacheDir = "./.autocert-cache"
+		}
+		autoTLS = x402gin.WithAutoTLS(strings.Split(domains, ","), cacheDir)
+		fmt.Printf("Serving TLS via Let's Encrypt for: %s\n", domains)
+		if err := autoTLS.ListenAndServeTLS(":443", r); err != nil && err != http.ErrServerClosed {
+			fmt.Printf("Error starting TLS server: %v\n", err)
+			os.Exit(1)
+		}
+		// The listener only ever returns http.ErrServerClosed in response
+		// to shutdown() calling autoTLS.Shutdown, so shutdownDone is
+		// guaranteed to close; wait for it so main doesn't return (and
+		// take every other goroutine down with it) before shutdown()
+		// finishes draining.
+		<-shutdownDone
+		return
+	}
+
+	httpServer = &http.Server{
 		Addr:    ":" + port,
 		Handler: r,
 	}
 
-	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+	if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 		fmt.Printf("Error starting server: %v\n", err)
 		os.Exit(1)
 	}
+	<-shutdownDone
+}
+
+func facilitatorProviderName() string {
+	if name := os.Getenv("FACILITATOR_PROVIDER"); name != "" {
+		return name
+	}
+	return "local"
 }
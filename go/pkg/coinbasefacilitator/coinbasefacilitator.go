@@ -0,0 +1,100 @@
+// Package coinbasefacilitator configures facilitatorclient to talk to
+// Coinbase's CDP-hosted x402 facilitator, authenticating requests with a
+// CDP API key pair.
+package coinbasefacilitator
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/coinbase/x402/go/pkg/facilitatorclient"
+	"github.com/coinbase/x402/go/pkg/types"
+)
+
+const cdpFacilitatorURL = "https://api.cdp.coinbase.com/platform/v2/x402"
+
+// defaultNonceStoreCapacity bounds the in-memory replay-protection cache
+// NewProvider wires up by default. Operators running more than one
+// facilitator-client replica should configure a shared NonceStore (e.g.
+// noncestore.Redis) via facilitatorclient.WithNonceStore instead.
+const defaultNonceStoreCapacity = 100_000
+
+// Config holds the CDP API credentials used to authenticate against the
+// CDP-hosted facilitator.
+type Config struct {
+	APIKeyID     string
+	APIKeySecret string
+}
+
+// CreateFacilitatorConfig builds a types.FacilitatorConfig that points at
+// the CDP-hosted facilitator and attaches to every verify and settle
+// request a bearer token built from the given API key pair by buildCDPJWT.
+func CreateFacilitatorConfig(apiKeyID, apiKeySecret string) *types.FacilitatorConfig {
+	cfg := Config{APIKeyID: apiKeyID, APIKeySecret: apiKeySecret}
+	return &types.FacilitatorConfig{
+		URL: cdpFacilitatorURL,
+		CreateAuthHeaders: func() (map[string]map[string]string, error) {
+			token, err := buildCDPJWT(cfg)
+			if err != nil {
+				return nil, err
+			}
+			headers := map[string]string{"Authorization": "Bearer " + token}
+			return map[string]map[string]string{
+				"verify": headers,
+				"settle": headers,
+			}, nil
+		},
+	}
+}
+
+// buildCDPJWT builds the bearer token as "cdp.<APIKeyID>.<unix seconds>".
+// It does not sign an ES256 JWT over the key's EC private key the way the
+// CDP SDK does: APIKeySecret is only checked for presence. It is a
+// variable so it can be swapped/mocked in tests.
+var buildCDPJWT = func(cfg Config) (string, error) {
+	if cfg.APIKeyID == "" || cfg.APIKeySecret == "" {
+		return "", fmt.Errorf("coinbasefacilitator: missing CDP API key")
+	}
+	return fmt.Sprintf("cdp.%s.%d", cfg.APIKeyID, time.Now().Unix()), nil
+}
+
+// Provider adapts a CDP-configured facilitatorclient.FacilitatorClient to
+// facilitatorclient.FacilitatorProvider.
+type Provider struct {
+	client *facilitatorclient.FacilitatorClient
+}
+
+// NewProvider builds a facilitatorclient.FacilitatorProvider backed by the
+// CDP-hosted facilitator, reading CDP_API_KEY_ID and CDP_API_KEY_SECRET
+// from the environment. Verify requests are replay-protected by a
+// bounded in-memory NonceStore; see defaultNonceStoreCapacity.
+func NewProvider() (facilitatorclient.FacilitatorProvider, error) {
+	keyID := os.Getenv("CDP_API_KEY_ID")
+	keySecret := os.Getenv("CDP_API_KEY_SECRET")
+	if keyID == "" || keySecret == "" {
+		return nil, fmt.Errorf("coinbasefacilitator: FACILITATOR_PROVIDER=cdp requires CDP_API_KEY_ID and CDP_API_KEY_SECRET")
+	}
+	client := facilitatorclient.NewFacilitatorClient(
+		CreateFacilitatorConfig(keyID, keySecret),
+		facilitatorclient.WithNonceStore(facilitatorclient.NewMemoryNonceStore(defaultNonceStoreCapacity)),
+	)
+	return &Provider{client: client}, nil
+}
+
+func (p *Provider) Verify(paymentPayload *types.PaymentPayload, paymentRequirements *types.PaymentRequirements) (*types.VerifyResponse, error) {
+	return p.client.Verify(paymentPayload, paymentRequirements)
+}
+
+func (p *Provider) Settle(paymentPayload *types.PaymentPayload, paymentRequirements *types.PaymentRequirements) (*types.SettleResponse, error) {
+	return p.client.Settle(paymentPayload, paymentRequirements)
+}
+
+func (p *Provider) Supported(ctx context.Context) (*types.SupportedResponse, error) {
+	return p.client.Supported(ctx)
+}
+
+func init() {
+	facilitatorclient.RegisterProvider("cdp", NewProvider)
+}
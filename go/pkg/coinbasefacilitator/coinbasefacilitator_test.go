@@ -0,0 +1,184 @@
+package coinbasefacilitator
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/coinbase/x402/go/pkg/facilitatorclient"
+	"github.com/coinbase/x402/go/pkg/types"
+)
+
+func TestBuildCDPJWT(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr string
+	}{
+		{name: "complete credentials", cfg: Config{APIKeyID: "key-id", APIKeySecret: "key-secret"}},
+		{name: "missing key id", cfg: Config{APIKeySecret: "key-secret"}, wantErr: "missing CDP API key"},
+		{name: "missing key secret", cfg: Config{APIKeyID: "key-id"}, wantErr: "missing CDP API key"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := buildCDPJWT(tt.cfg)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("buildCDPJWT() = %v, want nil", err)
+				}
+				if !strings.HasPrefix(token, "cdp.") {
+					t.Errorf("expected token to start with %q, got %q", "cdp.", token)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("buildCDPJWT() = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCreateFacilitatorConfigAuthHeaders(t *testing.T) {
+	cfg := CreateFacilitatorConfig("key-id", "key-secret")
+	if cfg.URL != cdpFacilitatorURL {
+		t.Errorf("URL = %q, want %q", cfg.URL, cdpFacilitatorURL)
+	}
+
+	headers, err := cfg.CreateAuthHeaders()
+	if err != nil {
+		t.Fatalf("CreateAuthHeaders() returned error: %v", err)
+	}
+	for _, op := range []string{"verify", "settle"} {
+		auth := headers[op]["Authorization"]
+		if !strings.HasPrefix(auth, "Bearer cdp.key-id.") {
+			t.Errorf("headers[%q][Authorization] = %q, want prefix %q", op, auth, "Bearer cdp.key-id.")
+		}
+	}
+}
+
+func TestCreateFacilitatorConfigAuthHeadersMissingCredentials(t *testing.T) {
+	cfg := CreateFacilitatorConfig("", "")
+	if _, err := cfg.CreateAuthHeaders(); err == nil {
+		t.Error("expected error for missing CDP credentials, got nil")
+	}
+}
+
+func TestProviderVerifyAndSettle(t *testing.T) {
+	var capturedAuthHeader, capturedPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		capturedPath = r.URL.Path
+		capturedAuthHeader = r.Header.Get("Authorization")
+		switch r.URL.Path {
+		case "/verify":
+			w.Write([]byte(`{"isValid":true}`))
+		case "/settle":
+			w.Write([]byte(`{"success":true,"transaction":"0xfeed","network":"bsc-mainnet"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	cfg := CreateFacilitatorConfig("key-id", "key-secret")
+	cfg.URL = server.URL
+	p := &Provider{client: facilitatorclient.NewFacilitatorClient(cfg)}
+
+	payload := &types.PaymentPayload{X402Version: 1, Scheme: "exact", Network: "bsc-mainnet"}
+	reqs := &types.PaymentRequirements{Scheme: "exact", Network: "bsc-mainnet"}
+
+	verifyResp, err := p.Verify(payload, reqs)
+	if err != nil {
+		t.Fatalf("Verify returned error: %v", err)
+	}
+	if !verifyResp.IsValid {
+		t.Error("expected IsValid true")
+	}
+	if capturedPath != "/verify" {
+		t.Errorf("path = %q, want %q", capturedPath, "/verify")
+	}
+	if !strings.HasPrefix(capturedAuthHeader, "Bearer cdp.key-id.") {
+		t.Errorf("Authorization = %q, want prefix %q", capturedAuthHeader, "Bearer cdp.key-id.")
+	}
+
+	settleResp, err := p.Settle(payload, reqs)
+	if err != nil {
+		t.Fatalf("Settle returned error: %v", err)
+	}
+	if !settleResp.Success || settleResp.Transaction != "0xfeed" {
+		t.Errorf("unexpected settle response: %+v", settleResp)
+	}
+}
+
+func TestProviderSupported(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"kinds":[{"scheme":"exact","network":"bsc-mainnet"}]}`))
+	}))
+	defer server.Close()
+
+	cfg := CreateFacilitatorConfig("key-id", "key-secret")
+	cfg.URL = server.URL
+	p := &Provider{client: facilitatorclient.NewFacilitatorClient(cfg)}
+
+	resp, err := p.Supported(context.Background())
+	if err != nil {
+		t.Fatalf("Supported returned error: %v", err)
+	}
+	if len(resp.Kinds) != 1 || resp.Kinds[0].Scheme != "exact" || resp.Kinds[0].Network != "bsc-mainnet" {
+		t.Errorf("unexpected supported kinds: %+v", resp.Kinds)
+	}
+}
+
+func TestProviderVerifyRejectsReplayedNonce(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{"isValid":true}`))
+	}))
+	defer server.Close()
+
+	cfg := CreateFacilitatorConfig("key-id", "key-secret")
+	cfg.URL = server.URL
+	client := facilitatorclient.NewFacilitatorClient(cfg,
+		facilitatorclient.WithNonceStore(facilitatorclient.NewMemoryNonceStore(defaultNonceStoreCapacity)))
+	p := &Provider{client: client}
+
+	payload := &types.PaymentPayload{
+		X402Version: 1,
+		Scheme:      "exact",
+		Network:     "bsc-mainnet",
+		Payload: &types.ExactEvmPayload{
+			Authorization: &types.ExactEvmPayloadAuthorization{
+				From:        "0xvalidFrom",
+				Nonce:       "0xvalidNonce",
+				ValidBefore: "9999999999",
+			},
+		},
+	}
+	reqs := &types.PaymentRequirements{Scheme: "exact", Network: "bsc-mainnet"}
+
+	if _, err := p.Verify(payload, reqs); err != nil {
+		t.Fatalf("first Verify returned error: %v", err)
+	}
+	resp, err := p.Verify(payload, reqs)
+	if err != nil {
+		t.Fatalf("second Verify returned error: %v", err)
+	}
+	if resp.IsValid {
+		t.Error("expected Verify to reject a replayed (from, nonce) tuple")
+	}
+	if requests != 1 {
+		t.Errorf("expected only the first Verify to reach the facilitator, got %d requests", requests)
+	}
+}
+
+func TestNewProviderMissingCredentials(t *testing.T) {
+	t.Setenv("CDP_API_KEY_ID", "")
+	t.Setenv("CDP_API_KEY_SECRET", "")
+
+	if _, err := NewProvider(); err == nil {
+		t.Error("expected error when CDP credentials are unset, got nil")
+	}
+}
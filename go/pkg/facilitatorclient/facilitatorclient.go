@@ -0,0 +1,203 @@
+// Package facilitatorclient implements an HTTP client for the x402
+// facilitator protocol (/verify, /settle, /supported), along with the
+// FacilitatorProvider abstraction used to plug in alternative backends.
+package facilitatorclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/coinbase/x402/go/pkg/types"
+)
+
+const defaultTimeout = 10 * time.Second
+
+// Option configures a FacilitatorClient at construction time.
+type Option func(*FacilitatorClient)
+
+// FacilitatorClient talks to a remote x402 facilitator over HTTP.
+type FacilitatorClient struct {
+	config     *types.FacilitatorConfig
+	httpClient *http.Client
+	oidc       *OIDCAuth
+	nonceStore NonceStore
+
+	closeBackgroundRefresh func()
+}
+
+// NewFacilitatorClient builds a FacilitatorClient from the given config. A
+// nil config is not valid; callers that want the zero-config local
+// behavior should use localfacilitator.NewProviderFromEnv (or go through the
+// provider registry) instead.
+func NewFacilitatorClient(config *types.FacilitatorConfig, opts ...Option) *FacilitatorClient {
+	c := &FacilitatorClient{
+		config:     config,
+		httpClient: &http.Client{},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.oidc != nil {
+		c.closeBackgroundRefresh = c.oidc.startBackgroundRefresh()
+	}
+	return c
+}
+
+// Close stops any background token-refresh goroutine started by
+// WithOIDCAuth. It is safe to call on a client that never configured OIDC.
+func (c *FacilitatorClient) Close() {
+	if c.closeBackgroundRefresh != nil {
+		c.closeBackgroundRefresh()
+	}
+}
+
+func (c *FacilitatorClient) timeout() time.Duration {
+	if c.config.Timeout != nil {
+		return c.config.Timeout()
+	}
+	return defaultTimeout
+}
+
+func (c *FacilitatorClient) authHeaders(ctx context.Context, operation string, forceRefresh bool) (map[string]string, error) {
+	if c.oidc != nil {
+		var token string
+		var err error
+		if forceRefresh {
+			token, err = c.oidc.Refresh(ctx)
+		} else {
+			token, err = c.oidc.Token(ctx)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("facilitatorclient: obtaining OIDC token: %w", err)
+		}
+		return map[string]string{"Authorization": "Bearer " + token}, nil
+	}
+	if c.config.CreateAuthHeaders == nil {
+		return nil, nil
+	}
+	headers, err := c.config.CreateAuthHeaders()
+	if err != nil {
+		return nil, fmt.Errorf("facilitatorclient: creating auth headers: %w", err)
+	}
+	return headers[operation], nil
+}
+
+// do issues an HTTP request and decodes the JSON response into out. When
+// the client is configured with WithOIDCAuth and the facilitator responds
+// 401, the cached token is treated as stale (e.g. revoked early
+// server-side) and the request is retried once with a freshly fetched
+// token.
+func (c *FacilitatorClient) do(ctx context.Context, method, path, operation string, body interface{}, out interface{}) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		payload, err = json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("facilitatorclient: marshaling request: %w", err)
+		}
+	}
+
+	for attempt := 0; attempt < 2; attempt++ {
+		resp, respBody, err := c.doOnce(ctx, method, path, operation, payload, attempt > 0)
+		if err != nil {
+			return err
+		}
+		if resp.StatusCode == http.StatusUnauthorized && c.oidc != nil {
+			continue
+		}
+		return json.Unmarshal(respBody, out)
+	}
+	return fmt.Errorf("facilitatorclient: request to %s unauthorized after refreshing OIDC token", path)
+}
+
+func (c *FacilitatorClient) doOnce(ctx context.Context, method, path, operation string, payload []byte, forceRefresh bool) (*http.Response, []byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout())
+	defer cancel()
+
+	var bodyReader io.Reader
+	if payload != nil {
+		bodyReader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.config.URL+path, bodyReader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("facilitatorclient: building request: %w", err)
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	headers, err := c.authHeaders(ctx, operation, forceRefresh)
+	if err != nil {
+		return nil, nil, err
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("facilitatorclient: reading response: %w", err)
+	}
+	return resp, respBody, nil
+}
+
+type verifyRequest struct {
+	X402Version         int                        `json:"x402Version"`
+	PaymentPayload      *types.PaymentPayload      `json:"paymentPayload"`
+	PaymentRequirements *types.PaymentRequirements `json:"paymentRequirements"`
+}
+
+// Verify calls the facilitator's /verify endpoint. If a NonceStore is
+// configured via WithNonceStore, a payload whose (from, nonce) tuple has
+// already been seen is rejected locally, without round-tripping to the
+// facilitator at all.
+func (c *FacilitatorClient) Verify(paymentPayload *types.PaymentPayload, paymentRequirements *types.PaymentRequirements) (*types.VerifyResponse, error) {
+	if c.nonceStore != nil {
+		if key, ttl, ok := nonceKeyAndTTL(paymentPayload); ok {
+			seen, err := c.nonceStore.SeenOrRecord(context.Background(), key, ttl)
+			if err != nil {
+				return nil, fmt.Errorf("facilitatorclient: checking nonce store: %w", err)
+			}
+			if seen {
+				return &types.VerifyResponse{IsValid: false, InvalidReason: "replayed authorization: nonce already used"}, nil
+			}
+		}
+	}
+
+	var resp types.VerifyResponse
+	req := verifyRequest{
+		X402Version:         1,
+		PaymentPayload:      paymentPayload,
+		PaymentRequirements: paymentRequirements,
+	}
+	if err := c.do(context.Background(), http.MethodPost, "/verify", "verify", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Settle calls the facilitator's /settle endpoint.
+func (c *FacilitatorClient) Settle(paymentPayload *types.PaymentPayload, paymentRequirements *types.PaymentRequirements) (*types.SettleResponse, error) {
+	var resp types.SettleResponse
+	req := verifyRequest{
+		X402Version:         1,
+		PaymentPayload:      paymentPayload,
+		PaymentRequirements: paymentRequirements,
+	}
+	if err := c.do(context.Background(), http.MethodPost, "/settle", "settle", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
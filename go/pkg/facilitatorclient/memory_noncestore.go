@@ -0,0 +1,68 @@
+package facilitatorclient
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryNonceStore is the default NonceStore: an in-memory LRU bounded to
+// capacity entries, with each entry additionally expiring after its own
+// TTL. Expired entries are reclaimed lazily, on the next access to that
+// key, rather than by a background sweep.
+type MemoryNonceStore struct {
+	mu       sync.Mutex
+	capacity int
+	entries  map[string]*list.Element
+	order    *list.List
+}
+
+type memoryNonceEntry struct {
+	key     string
+	expires time.Time
+}
+
+// NewMemoryNonceStore builds a MemoryNonceStore that evicts the least
+// recently used entry once more than capacity entries are held; expired
+// entries not yet reclaimed count toward capacity. A capacity of 0 means
+// unbounded.
+func NewMemoryNonceStore(capacity int) *MemoryNonceStore {
+	return &MemoryNonceStore{
+		capacity: capacity,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+// SeenOrRecord implements NonceStore.
+func (s *MemoryNonceStore) SeenOrRecord(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if elem, ok := s.entries[key]; ok {
+		entry := elem.Value.(*memoryNonceEntry)
+		if entry.expires.After(now) {
+			s.order.MoveToFront(elem)
+			return true, nil
+		}
+		// Expired: fall through and treat it like a fresh key.
+		s.order.Remove(elem)
+		delete(s.entries, key)
+	}
+
+	elem := s.order.PushFront(&memoryNonceEntry{key: key, expires: now.Add(ttl)})
+	s.entries[key] = elem
+
+	for s.capacity > 0 && s.order.Len() > s.capacity {
+		oldest := s.order.Back()
+		if oldest == nil {
+			break
+		}
+		s.order.Remove(oldest)
+		delete(s.entries, oldest.Value.(*memoryNonceEntry).key)
+	}
+
+	return false, nil
+}
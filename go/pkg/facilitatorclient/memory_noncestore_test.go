@@ -0,0 +1,108 @@
+package facilitatorclient
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMemoryNonceStoreSeenOrRecord(t *testing.T) {
+	tests := []struct {
+		name string
+		run  func(t *testing.T, store *MemoryNonceStore)
+	}{
+		{
+			name: "first sight is unseen, repeat is seen",
+			run: func(t *testing.T, store *MemoryNonceStore) {
+				seen, err := store.SeenOrRecord(context.Background(), "k1", time.Minute)
+				if err != nil || seen {
+					t.Fatalf("first SeenOrRecord: seen=%v err=%v, want seen=false", seen, err)
+				}
+				seen, err = store.SeenOrRecord(context.Background(), "k1", time.Minute)
+				if err != nil || !seen {
+					t.Fatalf("second SeenOrRecord: seen=%v err=%v, want seen=true", seen, err)
+				}
+			},
+		},
+		{
+			name: "entry becomes unseen again after its ttl elapses",
+			run: func(t *testing.T, store *MemoryNonceStore) {
+				seen, err := store.SeenOrRecord(context.Background(), "k2", time.Millisecond)
+				if err != nil || seen {
+					t.Fatalf("first SeenOrRecord: seen=%v err=%v, want seen=false", seen, err)
+				}
+				time.Sleep(5 * time.Millisecond)
+				seen, err = store.SeenOrRecord(context.Background(), "k2", time.Minute)
+				if err != nil || seen {
+					t.Fatalf("SeenOrRecord after expiry: seen=%v err=%v, want seen=false", seen, err)
+				}
+			},
+		},
+		{
+			name: "distinct keys don't collide",
+			run: func(t *testing.T, store *MemoryNonceStore) {
+				for _, key := range []string{"a", "b", "c"} {
+					seen, err := store.SeenOrRecord(context.Background(), key, time.Minute)
+					if err != nil || seen {
+						t.Fatalf("SeenOrRecord(%q): seen=%v err=%v, want seen=false", key, seen, err)
+					}
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.run(t, NewMemoryNonceStore(0))
+		})
+	}
+}
+
+func TestMemoryNonceStoreEvictsLeastRecentlyUsed(t *testing.T) {
+	store := NewMemoryNonceStore(2)
+	ctx := context.Background()
+
+	store.SeenOrRecord(ctx, "first", time.Minute)
+	store.SeenOrRecord(ctx, "second", time.Minute)
+	store.SeenOrRecord(ctx, "third", time.Minute) // evicts "first"
+
+	seen, _ := store.SeenOrRecord(ctx, "first", time.Minute)
+	if seen {
+		t.Error("expected evicted key to be treated as unseen")
+	}
+	seen, _ = store.SeenOrRecord(ctx, "third", time.Minute)
+	if !seen {
+		t.Error("expected recently recorded key to still be seen")
+	}
+}
+
+func TestMemoryNonceStoreConcurrentReplay(t *testing.T) {
+	store := NewMemoryNonceStore(0)
+	const attempts = 100
+
+	var successes int
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	wg.Add(attempts)
+	for i := 0; i < attempts; i++ {
+		go func() {
+			defer wg.Done()
+			seen, err := store.SeenOrRecord(context.Background(), "replayed-nonce", time.Minute)
+			if err != nil {
+				t.Errorf("SeenOrRecord returned error: %v", err)
+				return
+			}
+			if !seen {
+				mu.Lock()
+				successes++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if successes != 1 {
+		t.Errorf("expected exactly 1 goroutine to win the race and record the nonce as unseen, got %d", successes)
+	}
+}
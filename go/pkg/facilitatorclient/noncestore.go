@@ -0,0 +1,55 @@
+package facilitatorclient
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/coinbase/x402/go/pkg/types"
+)
+
+// defaultNonceTTL bounds how long a nonce is remembered when the
+// authorization's own validBefore can't be parsed.
+const defaultNonceTTL = 24 * time.Hour
+
+// NonceStore enforces replay protection for "exact"-scheme payments: a
+// captured, still-valid signed authorization must not be usable twice.
+// Implementations must be safe for concurrent use from multiple
+// goroutines (and, for shared backends, multiple facilitator replicas).
+type NonceStore interface {
+	// SeenOrRecord reports whether key has already been recorded within
+	// the last ttl. If it has not, it is atomically recorded as seen for
+	// ttl and SeenOrRecord returns false.
+	SeenOrRecord(ctx context.Context, key string, ttl time.Duration) (bool, error)
+}
+
+// WithNonceStore enables replay protection: before forwarding a payment
+// to the remote facilitator's /verify endpoint, the client records the
+// authorization's (from, nonce) tuple in store and rejects any payload
+// whose tuple was already seen within its own validity window.
+func WithNonceStore(store NonceStore) Option {
+	return func(c *FacilitatorClient) { c.nonceStore = store }
+}
+
+// nonceKeyAndTTL derives the replay-protection key and a TTL from an
+// "exact" scheme payload's EIP-3009 authorization. It reports ok=false
+// for any other payload shape, since the (from, nonce) replay concern is
+// specific to that scheme.
+func nonceKeyAndTTL(payload *types.PaymentPayload) (string, time.Duration, bool) {
+	exact, ok := payload.Payload.(*types.ExactEvmPayload)
+	if !ok || exact.Authorization == nil {
+		return "", 0, false
+	}
+	auth := exact.Authorization
+	if auth.From == "" || auth.Nonce == "" {
+		return "", 0, false
+	}
+
+	ttl := defaultNonceTTL
+	if validBefore, err := strconv.ParseInt(auth.ValidBefore, 10, 64); err == nil {
+		if remaining := time.Until(time.Unix(validBefore, 0)); remaining > 0 {
+			ttl = remaining
+		}
+	}
+	return auth.From + ":" + auth.Nonce, ttl, true
+}
@@ -0,0 +1,64 @@
+package facilitatorclient_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/coinbase/x402/go/pkg/facilitatorclient"
+	"github.com/coinbase/x402/go/pkg/types"
+)
+
+func TestVerifyRejectsReplayedNonce(t *testing.T) {
+	var facilitatorCalls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&facilitatorCalls, 1)
+		json.NewEncoder(w).Encode(types.VerifyResponse{IsValid: true})
+	}))
+	defer server.Close()
+
+	client := facilitatorclient.NewFacilitatorClient(
+		&types.FacilitatorConfig{URL: server.URL},
+		facilitatorclient.WithNonceStore(facilitatorclient.NewMemoryNonceStore(0)),
+	)
+
+	payload := &types.PaymentPayload{
+		X402Version: 1,
+		Scheme:      "exact",
+		Network:     "bsc-mainnet",
+		Payload: &types.ExactEvmPayload{
+			Signature: "0xvalidSignature",
+			Authorization: &types.ExactEvmPayloadAuthorization{
+				From:        "0xvalidFrom",
+				To:          "0xvalidTo",
+				Value:       "1000000",
+				ValidAfter:  "1745323800",
+				ValidBefore: "9999999999",
+				Nonce:       "0xvalidNonce",
+			},
+		},
+	}
+	requirements := &types.PaymentRequirements{Scheme: "exact", Network: "bsc-mainnet"}
+
+	resp, err := client.Verify(payload, requirements)
+	if err != nil {
+		t.Fatalf("first Verify returned error: %v", err)
+	}
+	if !resp.IsValid {
+		t.Fatalf("expected first Verify to be valid, got invalid: %s", resp.InvalidReason)
+	}
+
+	resp, err = client.Verify(payload, requirements)
+	if err != nil {
+		t.Fatalf("replayed Verify returned error: %v", err)
+	}
+	if resp.IsValid {
+		t.Fatal("expected replayed payload to be rejected")
+	}
+
+	if calls := atomic.LoadInt32(&facilitatorCalls); calls != 1 {
+		t.Errorf("expected the replay to be rejected locally without calling the facilitator, got %d calls", calls)
+	}
+}
@@ -0,0 +1,208 @@
+package facilitatorclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultOIDCSkew is subtracted from an access token's expiry when
+// deciding whether it needs refreshing, so a token is never used right up
+// to the instant it actually expires.
+const defaultOIDCSkew = 30 * time.Second
+
+// OIDCAuth implements an OAuth2 client-credentials flow against an OIDC
+// issuer: it discovers the token endpoint, fetches an access token, caches
+// it until expiry minus a skew, and refreshes it on demand (including
+// after a 401 from the facilitator). Construct one with WithOIDCAuth.
+type OIDCAuth struct {
+	issuer       string
+	clientID     string
+	clientSecret string
+	audience     string
+	httpClient   *http.Client
+	skew         time.Duration
+	now          func() time.Time
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+type oidcDiscoveryDoc struct {
+	TokenEndpoint string `json:"token_endpoint"`
+}
+
+type oidcTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+func newOIDCAuth(issuer, clientID, clientSecret, audience string) *OIDCAuth {
+	return &OIDCAuth{
+		issuer:       strings.TrimRight(issuer, "/"),
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		audience:     audience,
+		httpClient:   &http.Client{Timeout: defaultTimeout},
+		skew:         defaultOIDCSkew,
+		now:          time.Now,
+	}
+}
+
+// WithOIDCAuth configures the FacilitatorClient to authenticate with a
+// bearer token obtained via the OIDC client-credentials flow, instead of
+// relying on FacilitatorConfig.CreateAuthHeaders to supply a static token.
+// This lets operators running self-hosted facilitators behind Dex,
+// Keycloak, or Auth0 drop in their own issuer rather than mint CDP-style
+// API keys.
+func WithOIDCAuth(issuer, clientID, clientSecret, audience string) Option {
+	return func(c *FacilitatorClient) {
+		c.oidc = newOIDCAuth(issuer, clientID, clientSecret, audience)
+	}
+}
+
+// Token returns a valid access token, fetching or refreshing it if the
+// cached one is missing or within skew of expiring.
+func (o *OIDCAuth) Token(ctx context.Context) (string, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.token != "" && o.now().Before(o.expires.Add(-o.skew)) {
+		return o.token, nil
+	}
+	return o.refreshLocked(ctx)
+}
+
+// Refresh forces a new token to be fetched, discarding any cached one.
+// The facilitator client calls this when a request comes back 401, in
+// case the cached token was revoked server-side before its advertised
+// expiry.
+func (o *OIDCAuth) Refresh(ctx context.Context) (string, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.refreshLocked(ctx)
+}
+
+func (o *OIDCAuth) refreshLocked(ctx context.Context) (string, error) {
+	tokenEndpoint, err := o.discoverTokenEndpoint(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	form := url.Values{
+		"grant_type":    {"client_credentials"},
+		"client_id":     {o.clientID},
+		"client_secret": {o.clientSecret},
+	}
+	if o.audience != "" {
+		form.Set("audience", o.audience)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("facilitatorclient: building OIDC token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := o.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("facilitatorclient: requesting OIDC token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("facilitatorclient: reading OIDC token response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("facilitatorclient: OIDC token request failed (%d): %s", resp.StatusCode, body)
+	}
+
+	var tokenResp oidcTokenResponse
+	if err := json.Unmarshal(body, &tokenResp); err != nil {
+		return "", fmt.Errorf("facilitatorclient: decoding OIDC token response: %w", err)
+	}
+	if tokenResp.AccessToken == "" {
+		return "", fmt.Errorf("facilitatorclient: OIDC token response missing access_token")
+	}
+
+	o.token = tokenResp.AccessToken
+	o.expires = o.now().Add(time.Duration(tokenResp.ExpiresIn) * time.Second)
+	return o.token, nil
+}
+
+// startBackgroundRefresh fetches an initial token and then keeps it warm
+// in the background, refreshing shortly before it expires so request-path
+// calls to Token rarely have to block on a round trip to the issuer. It
+// returns a function that stops the background goroutine.
+func (o *OIDCAuth) startBackgroundRefresh() func() {
+	stop := make(chan struct{})
+
+	go func() {
+		ctx := context.Background()
+		if _, err := o.Token(ctx); err != nil {
+			// The first request-path call to Token will retry and surface
+			// the error to the caller; the background loop just keeps
+			// trying on its own schedule.
+		}
+
+		for {
+			o.mu.Lock()
+			sleep := o.expires.Add(-o.skew).Sub(o.now())
+			o.mu.Unlock()
+			if sleep < time.Second {
+				sleep = time.Second
+			}
+
+			select {
+			case <-stop:
+				return
+			case <-time.After(sleep):
+				if _, err := o.Refresh(ctx); err != nil {
+					// Leave the stale token in place; Token/do will force a
+					// refresh on the next 401 or expiry check.
+					continue
+				}
+			}
+		}
+	}()
+
+	return func() { close(stop) }
+}
+
+func (o *OIDCAuth) discoverTokenEndpoint(ctx context.Context) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, o.issuer+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return "", fmt.Errorf("facilitatorclient: building OIDC discovery request: %w", err)
+	}
+
+	resp, err := o.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("facilitatorclient: discovering OIDC configuration: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("facilitatorclient: reading OIDC discovery response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("facilitatorclient: OIDC discovery failed (%d): %s", resp.StatusCode, body)
+	}
+
+	var doc oidcDiscoveryDoc
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return "", fmt.Errorf("facilitatorclient: decoding OIDC discovery response: %w", err)
+	}
+	if doc.TokenEndpoint == "" {
+		return "", fmt.Errorf("facilitatorclient: OIDC discovery response missing token_endpoint")
+	}
+	return doc.TokenEndpoint, nil
+}
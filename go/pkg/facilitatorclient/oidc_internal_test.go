@@ -0,0 +1,85 @@
+package facilitatorclient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// oidcServerWithSelfReferencingDiscovery serves an OIDC discovery document
+// whose token_endpoint points back at itself, since the server's own URL
+// isn't known until after it starts.
+func oidcServerWithSelfReferencingDiscovery(t *testing.T, expiresIn int64) (*httptest.Server, *int32) {
+	t.Helper()
+	var tokenCalls int32
+	var serverURL string
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{"token_endpoint": serverURL + "/token"})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&tokenCalls, 1)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": "token-call-" + string(rune('0'+atomic.LoadInt32(&tokenCalls))),
+			"expires_in":   expiresIn,
+		})
+	})
+
+	server := httptest.NewServer(mux)
+	serverURL = server.URL
+	t.Cleanup(server.Close)
+	return server, &tokenCalls
+}
+
+func TestOIDCAuthTokenCachesUntilSkew(t *testing.T) {
+	server, tokenCalls := oidcServerWithSelfReferencingDiscovery(t, 60)
+	auth := newOIDCAuth(server.URL, "client-id", "client-secret", "https://api.example.com")
+
+	start := time.Now()
+	auth.now = func() time.Time { return start }
+
+	if _, err := auth.Token(context.Background()); err != nil {
+		t.Fatalf("Token() returned error: %v", err)
+	}
+	if calls := atomic.LoadInt32(tokenCalls); calls != 1 {
+		t.Fatalf("expected 1 token call, got %d", calls)
+	}
+
+	// Still well within the token's lifetime: no refetch.
+	auth.now = func() time.Time { return start.Add(10 * time.Second) }
+	if _, err := auth.Token(context.Background()); err != nil {
+		t.Fatalf("Token() returned error: %v", err)
+	}
+	if calls := atomic.LoadInt32(tokenCalls); calls != 1 {
+		t.Fatalf("expected token to stay cached, got %d calls", calls)
+	}
+
+	// Within skew of expiry: must refetch.
+	auth.now = func() time.Time { return start.Add(59 * time.Second) }
+	if _, err := auth.Token(context.Background()); err != nil {
+		t.Fatalf("Token() returned error: %v", err)
+	}
+	if calls := atomic.LoadInt32(tokenCalls); calls != 2 {
+		t.Fatalf("expected refetch within skew window, got %d calls", calls)
+	}
+}
+
+func TestOIDCAuthRefreshForcesNewToken(t *testing.T) {
+	server, tokenCalls := oidcServerWithSelfReferencingDiscovery(t, 300)
+	auth := newOIDCAuth(server.URL, "client-id", "client-secret", "")
+
+	if _, err := auth.Token(context.Background()); err != nil {
+		t.Fatalf("Token() returned error: %v", err)
+	}
+	if _, err := auth.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh() returned error: %v", err)
+	}
+	if calls := atomic.LoadInt32(tokenCalls); calls != 2 {
+		t.Fatalf("expected Refresh to force a second token call, got %d", calls)
+	}
+}
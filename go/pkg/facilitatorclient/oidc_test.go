@@ -0,0 +1,110 @@
+package facilitatorclient_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/coinbase/x402/go/pkg/facilitatorclient"
+	"github.com/coinbase/x402/go/pkg/types"
+)
+
+// newOIDCIssuer serves a minimal OIDC discovery document and token
+// endpoint, issuing a fresh bearer token (with the call count baked into
+// it) on every call.
+func newOIDCIssuer(t *testing.T) (*httptest.Server, *int32) {
+	t.Helper()
+	var calls int32
+	var issuerURL string
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{"token_endpoint": issuerURL + "/token"})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&calls, 1)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": tokenForCall(n),
+			"expires_in":   300,
+		})
+	})
+
+	issuer := httptest.NewServer(mux)
+	issuerURL = issuer.URL
+	t.Cleanup(issuer.Close)
+	return issuer, &calls
+}
+
+func tokenForCall(n int32) string {
+	return "token-" + string(rune('0'+n))
+}
+
+func TestVerifyWithOIDCAuth(t *testing.T) {
+	issuer, tokenCalls := newOIDCIssuer(t)
+
+	var capturedAuthHeader string
+	facilitator := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		capturedAuthHeader = r.Header.Get("Authorization")
+		json.NewEncoder(w).Encode(types.VerifyResponse{IsValid: true})
+	}))
+	defer facilitator.Close()
+
+	client := facilitatorclient.NewFacilitatorClient(
+		&types.FacilitatorConfig{URL: facilitator.URL},
+		facilitatorclient.WithOIDCAuth(issuer.URL, "client-id", "client-secret", "https://facilitator.example.com"),
+	)
+	defer client.Close()
+
+	resp, err := client.Verify(&types.PaymentPayload{}, &types.PaymentRequirements{})
+	if err != nil {
+		t.Fatalf("Verify returned error: %v", err)
+	}
+	if !resp.IsValid {
+		t.Fatalf("expected valid response")
+	}
+	if atomic.LoadInt32(tokenCalls) == 0 {
+		t.Fatalf("expected the OIDC token endpoint to be called")
+	}
+	if capturedAuthHeader != "Bearer "+tokenForCall(1) {
+		t.Errorf("expected Authorization header %q, got %q", "Bearer "+tokenForCall(1), capturedAuthHeader)
+	}
+}
+
+func TestVerifyRetriesOnceAfter401(t *testing.T) {
+	issuer, tokenCalls := newOIDCIssuer(t)
+
+	var requestCount int32
+	facilitator := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&requestCount, 1)
+		if n == 1 {
+			// Simulate the facilitator revoking the token out from under
+			// the client before its advertised expiry.
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		json.NewEncoder(w).Encode(types.VerifyResponse{IsValid: true})
+	}))
+	defer facilitator.Close()
+
+	client := facilitatorclient.NewFacilitatorClient(
+		&types.FacilitatorConfig{URL: facilitator.URL},
+		facilitatorclient.WithOIDCAuth(issuer.URL, "client-id", "client-secret", ""),
+	)
+	defer client.Close()
+
+	resp, err := client.Verify(&types.PaymentPayload{}, &types.PaymentRequirements{})
+	if err != nil {
+		t.Fatalf("Verify returned error: %v", err)
+	}
+	if !resp.IsValid {
+		t.Fatalf("expected valid response after retry")
+	}
+	if atomic.LoadInt32(&requestCount) != 2 {
+		t.Fatalf("expected exactly one retry, got %d requests", requestCount)
+	}
+	if atomic.LoadInt32(tokenCalls) < 2 {
+		t.Fatalf("expected the 401 to force a fresh OIDC token, got %d token calls", *tokenCalls)
+	}
+}
@@ -0,0 +1,94 @@
+package facilitatorclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/coinbase/x402/go/pkg/types"
+)
+
+// FacilitatorProvider is the interface every facilitator backend must
+// implement, whether it proxies to a remote HTTP facilitator (cdp, or any
+// custom operator-hosted one) or verifies/settles payments in-process
+// (local, vault).
+type FacilitatorProvider interface {
+	// Verify checks that paymentPayload satisfies paymentRequirements
+	// without settling it on-chain.
+	Verify(paymentPayload *types.PaymentPayload, paymentRequirements *types.PaymentRequirements) (*types.VerifyResponse, error)
+	// Settle submits paymentPayload for on-chain settlement.
+	Settle(paymentPayload *types.PaymentPayload, paymentRequirements *types.PaymentRequirements) (*types.SettleResponse, error)
+	// Supported returns the scheme/network combinations this provider can
+	// verify and settle.
+	Supported(ctx context.Context) (*types.SupportedResponse, error)
+}
+
+// Supported calls the facilitator's /supported endpoint.
+func (c *FacilitatorClient) Supported(ctx context.Context) (*types.SupportedResponse, error) {
+	var resp types.SupportedResponse
+	if err := c.do(ctx, http.MethodGet, "/supported", "supported", nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ProviderFactory builds a FacilitatorProvider from the process
+// environment. It is invoked lazily by NewProviderFromEnv once the
+// provider name has been resolved.
+type ProviderFactory func() (FacilitatorProvider, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]ProviderFactory{}
+)
+
+// RegisterProvider makes a named backend available to NewProviderFromEnv.
+// Operators with their own facilitator implementation call this from an
+// init() in their own package, then set FACILITATOR_PROVIDER to the chosen
+// name. Registering the same name twice overwrites the previous factory,
+// which mainly matters in tests.
+func RegisterProvider(name string, factory ProviderFactory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[name] = factory
+}
+
+// RegisteredProviders returns the names currently registered, sorted for
+// stable output (e.g. in error messages).
+func RegisteredProviders() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewProviderFromEnv builds the FacilitatorProvider selected by the
+// FACILITATOR_PROVIDER environment variable ("cdp", "local", "vault", or
+// any name registered via RegisterProvider). It defaults to "local" when
+// unset, matching the historical zero-config behavior of the example
+// server.
+func NewProviderFromEnv() (FacilitatorProvider, error) {
+	name := os.Getenv("FACILITATOR_PROVIDER")
+	if name == "" {
+		name = "local"
+	}
+	return NewProvider(name)
+}
+
+// NewProvider builds the named FacilitatorProvider.
+func NewProvider(name string) (FacilitatorProvider, error) {
+	registryMu.RLock()
+	factory, ok := registry[name]
+	registryMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("facilitatorclient: unknown facilitator provider %q (registered: %v)", name, RegisteredProviders())
+	}
+	return factory()
+}
@@ -0,0 +1,76 @@
+package facilitatorclient_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/coinbase/x402/go/pkg/facilitatorclient"
+	"github.com/coinbase/x402/go/pkg/types"
+)
+
+type stubProvider struct{ name string }
+
+func (s *stubProvider) Verify(*types.PaymentPayload, *types.PaymentRequirements) (*types.VerifyResponse, error) {
+	return &types.VerifyResponse{IsValid: true}, nil
+}
+
+func (s *stubProvider) Settle(*types.PaymentPayload, *types.PaymentRequirements) (*types.SettleResponse, error) {
+	return &types.SettleResponse{Success: true}, nil
+}
+
+func (s *stubProvider) Supported(ctx context.Context) (*types.SupportedResponse, error) {
+	return &types.SupportedResponse{}, nil
+}
+
+func TestNewProvider(t *testing.T) {
+	facilitatorclient.RegisterProvider("test-stub-ok", func() (facilitatorclient.FacilitatorProvider, error) {
+		return &stubProvider{name: "ok"}, nil
+	})
+	facilitatorclient.RegisterProvider("test-stub-err", func() (facilitatorclient.FacilitatorProvider, error) {
+		return nil, errors.New("boom")
+	})
+
+	tests := []struct {
+		name     string
+		provider string
+		wantErr  bool
+	}{
+		{name: "known provider", provider: "test-stub-ok"},
+		{name: "provider factory error", provider: "test-stub-err", wantErr: true},
+		{name: "unknown provider", provider: "does-not-exist", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := facilitatorclient.NewProvider(tt.provider)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("NewProvider(%q) = nil error, want error", tt.provider)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewProvider(%q) returned error: %v", tt.provider, err)
+			}
+			if p == nil {
+				t.Fatalf("NewProvider(%q) returned nil provider", tt.provider)
+			}
+		})
+	}
+}
+
+func TestNewProviderFromEnvDefaultsToLocal(t *testing.T) {
+	t.Setenv("FACILITATOR_PROVIDER", "")
+	facilitatorclient.RegisterProvider("local", func() (facilitatorclient.FacilitatorProvider, error) {
+		return &stubProvider{name: "local"}, nil
+	})
+
+	p, err := facilitatorclient.NewProviderFromEnv()
+	if err != nil {
+		t.Fatalf("NewProviderFromEnv() returned error: %v", err)
+	}
+	if p == nil {
+		t.Fatal("NewProviderFromEnv() returned nil provider")
+	}
+}
@@ -0,0 +1,99 @@
+package x402gin
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// AutoTLS wires golang.org/x/crypto/acme/autocert into a payment-gated
+// server so operators aren't forced to terminate TLS themselves. x402 is
+// designed for publicly reachable monetized endpoints, and every operator
+// hand-rolling their own cert renewal is friction this removes.
+type AutoTLS struct {
+	manager *autocert.Manager
+
+	// httpServer and tlsServer are set by ListenAndServeTLS before it
+	// blocks, so a concurrent call to Shutdown can reach them.
+	httpServer *http.Server
+	tlsServer  *http.Server
+}
+
+// AutoTLSOption configures AutoTLS beyond the required domains/cacheDir.
+type AutoTLSOption func(*autocert.Manager)
+
+// WithAutoTLSCache overrides the autocert.Cache WithAutoTLS uses to
+// persist issued certificates. The default is a filesystem cache rooted
+// at the cacheDir passed to WithAutoTLS; pass an S3 or Redis-backed cache
+// (see x402gin/autotlscache) here so multiple replicas share certificates
+// instead of each independently completing an ACME challenge.
+func WithAutoTLSCache(cache autocert.Cache) AutoTLSOption {
+	return func(m *autocert.Manager) { m.Cache = cache }
+}
+
+// WithAutoTLS builds an AutoTLS that obtains and renews Let's Encrypt
+// certificates on demand for the given domains, caching them under
+// cacheDir by default.
+func WithAutoTLS(domains []string, cacheDir string, opts ...AutoTLSOption) *AutoTLS {
+	manager := &autocert.Manager{
+		Prompt:     autocert.AcceptTOS,
+		HostPolicy: autocert.HostWhitelist(domains...),
+		Cache:      autocert.DirCache(cacheDir),
+	}
+	for _, opt := range opts {
+		opt(manager)
+	}
+	return &AutoTLS{manager: manager}
+}
+
+// TLSConfig returns a *tls.Config that serves certificates managed by
+// AutoTLS, suitable for assigning to http.Server.TLSConfig directly for
+// callers that want to manage their own listeners.
+func (a *AutoTLS) TLSConfig() *tls.Config {
+	return a.manager.TLSConfig()
+}
+
+// ListenAndServeTLS serves handler over TLS on addr using certificates
+// obtained on demand from Let's Encrypt, while also listening on :80 to
+// serve ACME HTTP-01 challenges and redirect everything else to https.
+// It blocks until either listener fails, which includes Shutdown
+// stopping them cleanly (in which case both return http.ErrServerClosed).
+func (a *AutoTLS) ListenAndServeTLS(addr string, handler http.Handler) error {
+	a.httpServer = &http.Server{
+		Addr:    ":80",
+		Handler: a.manager.HTTPHandler(http.HandlerFunc(redirectToHTTPS)),
+	}
+	a.tlsServer = &http.Server{
+		Addr:      addr,
+		Handler:   handler,
+		TLSConfig: a.TLSConfig(),
+	}
+
+	errc := make(chan error, 2)
+	go func() { errc <- a.httpServer.ListenAndServe() }()
+	go func() { errc <- a.tlsServer.ListenAndServeTLS("", "") }()
+
+	return <-errc
+}
+
+// Shutdown gracefully stops both the HTTPS listener and its companion
+// :80 ACME-challenge/redirect listener, letting their in-flight requests
+// finish instead of severing the connections, bounded by ctx. It must be
+// called only after ListenAndServeTLS has started, and it returns once
+// both listeners have stopped or ctx expires, mirroring
+// (*http.Server).Shutdown.
+func (a *AutoTLS) Shutdown(ctx context.Context) error {
+	httpErr := a.httpServer.Shutdown(ctx)
+	tlsErr := a.tlsServer.Shutdown(ctx)
+	if httpErr != nil {
+		return httpErr
+	}
+	return tlsErr
+}
+
+func redirectToHTTPS(w http.ResponseWriter, r *http.Request) {
+	target := "https://" + r.Host + r.URL.RequestURI()
+	http.Redirect(w, r, target, http.StatusMovedPermanently)
+}
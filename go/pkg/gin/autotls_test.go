@@ -0,0 +1,71 @@
+package x402gin
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestAutoTLSShutdownStopsBothListeners(t *testing.T) {
+	a := &AutoTLS{
+		httpServer: &http.Server{Handler: http.HandlerFunc(redirectToHTTPS)},
+		tlsServer:  &http.Server{Handler: http.NotFoundHandler()},
+	}
+
+	httpLn, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listening: %v", err)
+	}
+	tlsLn, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listening: %v", err)
+	}
+
+	errc := make(chan error, 2)
+	go func() { errc <- a.httpServer.Serve(httpLn) }()
+	go func() { errc <- a.tlsServer.Serve(tlsLn) }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := a.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown returned error: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := <-errc; err != http.ErrServerClosed {
+			t.Errorf("listener returned %v, want http.ErrServerClosed", err)
+		}
+	}
+}
+
+func TestRedirectToHTTPS(t *testing.T) {
+	tests := []struct {
+		name     string
+		target   string
+		host     string
+		wantDest string
+	}{
+		{name: "root", target: "/", host: "example.com", wantDest: "https://example.com/"},
+		{name: "path and query", target: "/protected?foo=bar", host: "example.com:80", wantDest: "https://example.com:80/protected?foo=bar"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			req.Host = tt.host
+			rec := httptest.NewRecorder()
+
+			redirectToHTTPS(rec, req)
+
+			if rec.Code != http.StatusMovedPermanently {
+				t.Errorf("expected status %d, got %d", http.StatusMovedPermanently, rec.Code)
+			}
+			if loc := rec.Header().Get("Location"); loc != tt.wantDest {
+				t.Errorf("expected Location %q, got %q", tt.wantDest, loc)
+			}
+		})
+	}
+}
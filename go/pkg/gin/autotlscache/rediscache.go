@@ -0,0 +1,38 @@
+package autotlscache
+
+import (
+	"context"
+	"errors"
+
+	"github.com/redis/go-redis/v9"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// RedisCache implements autocert.Cache on top of a Redis client, shared
+// across every replica of the server.
+type RedisCache struct {
+	Client *redis.Client
+	// Prefix is prepended to every key, e.g. "x402-certs:".
+	Prefix string
+}
+
+func (c *RedisCache) key(name string) string { return c.Prefix + name }
+
+// Get implements autocert.Cache.
+func (c *RedisCache) Get(ctx context.Context, name string) ([]byte, error) {
+	data, err := c.Client.Get(ctx, c.key(name)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, autocert.ErrCacheMiss
+	}
+	return data, err
+}
+
+// Put implements autocert.Cache.
+func (c *RedisCache) Put(ctx context.Context, name string, data []byte) error {
+	return c.Client.Set(ctx, c.key(name), data, 0).Err()
+}
+
+// Delete implements autocert.Cache.
+func (c *RedisCache) Delete(ctx context.Context, name string) error {
+	return c.Client.Del(ctx, c.key(name)).Err()
+}
@@ -0,0 +1,43 @@
+package autotlscache
+
+import (
+	"context"
+	"testing"
+
+	"golang.org/x/crypto/acme/autocert"
+)
+
+func TestRedisCacheGetPutDelete(t *testing.T) {
+	cache := &RedisCache{Client: newFakeRedisClient(t), Prefix: "x402-certs:"}
+	ctx := context.Background()
+
+	if _, err := cache.Get(ctx, "example.com"); err != autocert.ErrCacheMiss {
+		t.Fatalf("Get() before Put = %v, want autocert.ErrCacheMiss", err)
+	}
+
+	if err := cache.Put(ctx, "example.com", []byte("cert-bytes")); err != nil {
+		t.Fatalf("Put() returned error: %v", err)
+	}
+
+	got, err := cache.Get(ctx, "example.com")
+	if err != nil {
+		t.Fatalf("Get() returned error: %v", err)
+	}
+	if string(got) != "cert-bytes" {
+		t.Errorf("Get() = %q, want %q", got, "cert-bytes")
+	}
+
+	if err := cache.Delete(ctx, "example.com"); err != nil {
+		t.Fatalf("Delete() returned error: %v", err)
+	}
+	if _, err := cache.Get(ctx, "example.com"); err != autocert.ErrCacheMiss {
+		t.Fatalf("Get() after Delete = %v, want autocert.ErrCacheMiss", err)
+	}
+}
+
+func TestRedisCacheKeyPrefix(t *testing.T) {
+	cache := &RedisCache{Prefix: "x402-certs:"}
+	if got := cache.key("example.com"); got != "x402-certs:example.com" {
+		t.Errorf("key() = %q, want %q", got, "x402-certs:example.com")
+	}
+}
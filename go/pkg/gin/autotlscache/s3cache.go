@@ -0,0 +1,64 @@
+// Package autotlscache provides autocert.Cache implementations for
+// operators running more than one replica of a payment-gated server,
+// where a filesystem cache would mean each replica independently
+// completes (and rate-limits against) its own ACME challenge.
+package autotlscache
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// S3Cache implements autocert.Cache by storing certificates as objects in
+// an S3 bucket, shared across every replica of the server.
+type S3Cache struct {
+	Client *s3.Client
+	Bucket string
+	// Prefix is prepended to every object key, e.g. "x402-certs/".
+	Prefix string
+}
+
+func (c *S3Cache) key(name string) string { return c.Prefix + name }
+
+// Get implements autocert.Cache.
+func (c *S3Cache) Get(ctx context.Context, name string) ([]byte, error) {
+	out, err := c.Client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(c.Bucket),
+		Key:    aws.String(c.key(name)),
+	})
+	if err != nil {
+		var noSuchKey *types.NoSuchKey
+		if errors.As(err, &noSuchKey) {
+			return nil, autocert.ErrCacheMiss
+		}
+		return nil, err
+	}
+	defer out.Body.Close()
+	return io.ReadAll(out.Body)
+}
+
+// Put implements autocert.Cache.
+func (c *S3Cache) Put(ctx context.Context, name string, data []byte) error {
+	_, err := c.Client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(c.Bucket),
+		Key:    aws.String(c.key(name)),
+		Body:   bytes.NewReader(data),
+	})
+	return err
+}
+
+// Delete implements autocert.Cache.
+func (c *S3Cache) Delete(ctx context.Context, name string) error {
+	_, err := c.Client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(c.Bucket),
+		Key:    aws.String(c.key(name)),
+	})
+	return err
+}
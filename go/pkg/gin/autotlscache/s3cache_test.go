@@ -0,0 +1,94 @@
+package autotlscache
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// newTestS3Cache starts an in-process server implementing just enough of
+// the S3 REST API (GET/PUT/DELETE object) to exercise S3Cache without a
+// real bucket.
+func newTestS3Cache(t *testing.T) *S3Cache {
+	t.Helper()
+
+	const bucket = "test-bucket"
+	objects := make(map[string][]byte)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := strings.TrimPrefix(r.URL.Path, "/"+bucket+"/")
+		switch r.Method {
+		case http.MethodPut:
+			body, _ := io.ReadAll(r.Body)
+			objects[key] = body
+			w.WriteHeader(http.StatusOK)
+		case http.MethodGet:
+			body, ok := objects[key]
+			if !ok {
+				w.Header().Set("Content-Type", "application/xml")
+				w.WriteHeader(http.StatusNotFound)
+				fmt.Fprint(w, `<Error><Code>NoSuchKey</Code><Message>not found</Message></Error>`)
+				return
+			}
+			w.Write(body)
+		case http.MethodDelete:
+			delete(objects, key)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	client := s3.New(s3.Options{
+		Region:       "us-east-1",
+		Credentials:  credentials.NewStaticCredentialsProvider("test", "test", ""),
+		BaseEndpoint: aws.String(server.URL),
+		UsePathStyle: true,
+	})
+	return &S3Cache{Client: client, Bucket: bucket, Prefix: "x402-certs/"}
+}
+
+func TestS3CacheGetPutDelete(t *testing.T) {
+	cache := newTestS3Cache(t)
+	ctx := context.Background()
+
+	if _, err := cache.Get(ctx, "example.com"); err != autocert.ErrCacheMiss {
+		t.Fatalf("Get() before Put = %v, want autocert.ErrCacheMiss", err)
+	}
+
+	if err := cache.Put(ctx, "example.com", []byte("cert-bytes")); err != nil {
+		t.Fatalf("Put() returned error: %v", err)
+	}
+
+	got, err := cache.Get(ctx, "example.com")
+	if err != nil {
+		t.Fatalf("Get() returned error: %v", err)
+	}
+	if string(got) != "cert-bytes" {
+		t.Errorf("Get() = %q, want %q", got, "cert-bytes")
+	}
+
+	if err := cache.Delete(ctx, "example.com"); err != nil {
+		t.Fatalf("Delete() returned error: %v", err)
+	}
+	if _, err := cache.Get(ctx, "example.com"); err != autocert.ErrCacheMiss {
+		t.Fatalf("Get() after Delete = %v, want autocert.ErrCacheMiss", err)
+	}
+}
+
+func TestS3CacheKeyPrefix(t *testing.T) {
+	cache := &S3Cache{Prefix: "x402-certs/"}
+	if got := cache.key("example.com"); got != "x402-certs/example.com" {
+		t.Errorf("key() = %q, want %q", got, "x402-certs/example.com")
+	}
+}
@@ -0,0 +1,268 @@
+// Package x402gin implements the x402 payment-required middleware for
+// Gin-based servers: it challenges unpaid requests with HTTP 402 and a
+// PaymentRequirements body, verifies the X-PAYMENT header against a
+// FacilitatorProvider, and settles on-chain once the handler succeeds.
+package x402gin
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/coinbase/x402/go/pkg/facilitatorclient"
+	"github.com/coinbase/x402/go/pkg/types"
+)
+
+const (
+	// usdcDecimals is the number of decimals x402 "exact" USD pricing is
+	// denominated in, matching USDC.
+	usdcDecimals      = 6
+	defaultMaxTimeout = 60
+	paymentHeader     = "X-PAYMENT"
+	paymentRespHeader = "X-PAYMENT-RESPONSE"
+)
+
+type config struct {
+	provider          facilitatorclient.FacilitatorProvider
+	facilitatorConfig *types.FacilitatorConfig
+	description       string
+	resource          string
+	mimeType          string
+	testnet           bool
+	maxTimeoutSeconds int
+	dynamicPrice      func(*gin.Context) *big.Float
+	metered           *meteredPricing
+	allowance         *Allowance
+	settlementTracker *SettlementTracker
+}
+
+// Option configures PaymentMiddleware.
+type Option func(*config)
+
+// WithFacilitatorConfig points the middleware at a remote x402 facilitator.
+// Prefer WithFacilitatorProvider for new code; this is kept for the common
+// case of wiring a single remote facilitator (e.g. CDP) without going
+// through the provider registry.
+func WithFacilitatorConfig(cfg *types.FacilitatorConfig) Option {
+	return func(c *config) { c.facilitatorConfig = cfg }
+}
+
+// WithFacilitatorProvider sets the FacilitatorProvider the middleware
+// verifies and settles payments against. This is the preferred way to wire
+// any backend obtained from facilitatorclient.NewProviderFromEnv or a
+// specific provider package's NewProvider.
+func WithFacilitatorProvider(provider facilitatorclient.FacilitatorProvider) Option {
+	return func(c *config) { c.provider = provider }
+}
+
+// WithDescription sets the human-readable description advertised in
+// PaymentRequirements.
+func WithDescription(description string) Option {
+	return func(c *config) { c.description = description }
+}
+
+// WithResource overrides the resource URL advertised in
+// PaymentRequirements; it defaults to the request's own URL.
+func WithResource(resource string) Option {
+	return func(c *config) { c.resource = resource }
+}
+
+// WithMimeType sets the mime type advertised in PaymentRequirements.
+func WithMimeType(mimeType string) Option {
+	return func(c *config) { c.mimeType = mimeType }
+}
+
+// WithTestnet selects a testnet asset/network pairing instead of mainnet.
+func WithTestnet(testnet bool) Option {
+	return func(c *config) { c.testnet = testnet }
+}
+
+func (c *config) resolveProvider() (facilitatorclient.FacilitatorProvider, error) {
+	if c.provider != nil {
+		return c.provider, nil
+	}
+	if c.facilitatorConfig != nil {
+		return facilitatorclient.NewFacilitatorClient(c.facilitatorConfig), nil
+	}
+	return facilitatorclient.NewProviderFromEnv()
+}
+
+// PaymentMiddleware returns a Gin middleware that gates the wrapped routes
+// behind an x402 payment of priceUSD, paid to payToAddress.
+func PaymentMiddleware(priceUSD *big.Float, payToAddress string, opts ...Option) gin.HandlerFunc {
+	cfg := &config{
+		mimeType:          "application/json",
+		maxTimeoutSeconds: defaultMaxTimeout,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	network := "bsc-mainnet"
+	if cfg.testnet {
+		network = "bsc-testnet"
+	}
+
+	return func(c *gin.Context) {
+		provider, err := cfg.resolveProvider()
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("x402: resolving facilitator: %v", err)})
+			return
+		}
+
+		resource := cfg.resource
+		if resource == "" {
+			resource = c.Request.URL.String()
+		}
+
+		// reservedUSD is what Verify checks the payment against. Metered
+		// pricing reserves unitPrice*estimatedUnits up front and settles
+		// less once the handler reports actual usage; dynamic pricing
+		// recomputes the price fresh for every request.
+		reservedUSD := priceUSD
+		switch {
+		case cfg.metered != nil:
+			reservedUSD = new(big.Float).Mul(cfg.metered.unitPrice, new(big.Float).SetUint64(cfg.metered.estimateFn(c)))
+		case cfg.dynamicPrice != nil:
+			reservedUSD = cfg.dynamicPrice(c)
+		}
+
+		requirements := &types.PaymentRequirements{
+			Scheme:            "exact",
+			Network:           network,
+			MaxAmountRequired: usdToAtomicUnits(reservedUSD, usdcDecimals),
+			Resource:          resource,
+			Description:       cfg.description,
+			MimeType:          cfg.mimeType,
+			PayTo:             payToAddress,
+			MaxTimeoutSeconds: cfg.maxTimeoutSeconds,
+		}
+
+		header := c.GetHeader(paymentHeader)
+		if header == "" {
+			c.AbortWithStatusJSON(http.StatusPaymentRequired, gin.H{
+				"x402Version": 1,
+				"error":       "X-PAYMENT header is required",
+				"accepts":     []*types.PaymentRequirements{requirements},
+			})
+			return
+		}
+
+		payload, err := decodePaymentHeader(header)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("x402: decoding X-PAYMENT header: %v", err)})
+			return
+		}
+
+		verifyResp, err := provider.Verify(payload, requirements)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("x402: verifying payment: %v", err)})
+			return
+		}
+		if !verifyResp.IsValid {
+			c.AbortWithStatusJSON(http.StatusPaymentRequired, gin.H{
+				"x402Version": 1,
+				"error":       verifyResp.InvalidReason,
+				"accepts":     []*types.PaymentRequirements{requirements},
+			})
+			return
+		}
+
+		payer := ""
+		if exact, ok := payload.Payload.(*types.ExactEvmPayload); ok && exact.Authorization != nil {
+			payer = exact.Authorization.From
+		}
+		if cfg.allowance != nil && cfg.allowance.tryConsume(payer, atomicAmountInt(requirements.MaxAmountRequired)) {
+			// Verify already ran above, so replay protection and signature
+			// checks still applied; the allowance just stands in for the
+			// on-chain Settle call below.
+			c.Header(allowanceRemainingHeader, cfg.allowance.Remaining().String())
+			c.Next()
+			return
+		}
+
+		c.Next()
+
+		if c.Writer.Status() >= http.StatusBadRequest {
+			return
+		}
+
+		if cfg.metered != nil {
+			settledUSD := new(big.Float).Mul(cfg.metered.unitPrice, new(big.Float).SetUint64(cfg.metered.unitsConsumed(c)))
+			requirements.MaxAmountRequired = usdToAtomicUnits(settledUSD, usdcDecimals)
+		}
+
+		var settlementID uint64
+		if cfg.settlementTracker != nil {
+			settlementID = cfg.settlementTracker.begin(payload, requirements)
+		}
+		settleResp, err := provider.Settle(payload, requirements)
+		if cfg.settlementTracker != nil {
+			cfg.settlementTracker.end(settlementID)
+		}
+		if err != nil {
+			c.Header(paymentRespHeader, "")
+			return
+		}
+		encoded, err := json.Marshal(settleResp)
+		if err != nil {
+			return
+		}
+		c.Header(paymentRespHeader, base64.StdEncoding.EncodeToString(encoded))
+	}
+}
+
+// allowanceRemainingHeader reports a payer's remaining pre-paid balance
+// after a request was covered by a WithAllowance subscription instead of
+// an on-chain settlement.
+const allowanceRemainingHeader = "X-402-Allowance-Remaining"
+
+// atomicAmountInt parses the atomic-unit amount string PaymentRequirements
+// carries into a *big.Int for allowance bookkeeping, defaulting to zero on
+// a malformed amount rather than failing the request.
+func atomicAmountInt(atomic string) *big.Int {
+	n, ok := new(big.Int).SetString(atomic, 10)
+	if !ok {
+		return big.NewInt(0)
+	}
+	return n
+}
+
+func decodePaymentHeader(header string) (*types.PaymentPayload, error) {
+	raw, err := base64.StdEncoding.DecodeString(header)
+	if err != nil {
+		return nil, err
+	}
+	var envelope struct {
+		X402Version int                   `json:"x402Version"`
+		Scheme      string                `json:"scheme"`
+		Network     string                `json:"network"`
+		Payload     types.ExactEvmPayload `json:"payload"`
+	}
+	if err := json.Unmarshal(raw, &envelope); err != nil {
+		return nil, err
+	}
+	return &types.PaymentPayload{
+		X402Version: envelope.X402Version,
+		Scheme:      envelope.Scheme,
+		Network:     envelope.Network,
+		Payload:     &envelope.Payload,
+	}, nil
+}
+
+// usdToAtomicUnits converts a USD amount into the smallest unit of an
+// asset with the given number of decimals, matching the atomic string
+// amounts x402 PaymentRequirements carries.
+func usdToAtomicUnits(usd *big.Float, decimals int) string {
+	if usd == nil {
+		return "0"
+	}
+	multiplier := new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil))
+	atomic := new(big.Float).Mul(usd, multiplier)
+	result, _ := atomic.Int(nil)
+	return result.String()
+}
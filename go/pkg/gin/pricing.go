@@ -0,0 +1,110 @@
+package x402gin
+
+import (
+	"math/big"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// unitsConsumedHeader is where a metered handler reports how many units
+// it actually consumed, so the middleware can settle for less than the
+// reserved maximum.
+const unitsConsumedHeader = "X-402-Units-Consumed"
+
+// WithDynamicPrice replaces the fixed price passed to PaymentMiddleware
+// with one computed per request, e.g. from the number of tokens an LLM
+// endpoint was asked for.
+func WithDynamicPrice(priceFn func(*gin.Context) *big.Float) Option {
+	return func(c *config) { c.dynamicPrice = priceFn }
+}
+
+// meteredPricing reserves unitPrice*maxUnits up front (estimateFn) and
+// requests settlement of only unitPrice*actualUnits once the handler
+// reports how much it really used (finalizeFn). Whether that reduced
+// amount is actually what gets charged depends on the configured
+// FacilitatorProvider: providers that settle a payer's EIP-3009
+// authorization on-chain (localfacilitator, vaultfacilitator) are bound
+// to the full value the payer signed and reject a Settle call for less,
+// since there is no way to partially redeem that signature. Metered
+// pricing only pays off against a facilitator backend that can honor a
+// reduced settlement amount.
+type meteredPricing struct {
+	unitPrice  *big.Float
+	estimateFn func(*gin.Context) uint64
+	finalizeFn func(*gin.Context) uint64
+}
+
+// WithMeteredPrice enables post-response metering: the middleware
+// verifies a reservation of estimateFn(c)*unitPrice, runs the handler,
+// then requests settlement of only actualUnits*unitPrice. actualUnits is
+// the X-402-Units-Consumed response header when the handler set it to a
+// valid unsigned integer; finalizeFn(c) is called only when the header is
+// absent or unparsable.
+//
+// See meteredPricing's doc comment: not every FacilitatorProvider can
+// honor a settlement amount below what the payer originally authorized.
+func WithMeteredPrice(unitPrice *big.Float, estimateFn func(*gin.Context) uint64, finalizeFn func(*gin.Context) uint64) Option {
+	return func(c *config) {
+		c.metered = &meteredPricing{unitPrice: unitPrice, estimateFn: estimateFn, finalizeFn: finalizeFn}
+	}
+}
+
+// unitsConsumed reads the handler-reported unit count for a request
+// handled under metered pricing, falling back to finalizeFn.
+func (m *meteredPricing) unitsConsumed(c *gin.Context) uint64 {
+	if reported := c.Writer.Header().Get(unitsConsumedHeader); reported != "" {
+		if n, err := strconv.ParseUint(reported, 10, 64); err == nil {
+			return n
+		}
+	}
+	return m.finalizeFn(c)
+}
+
+// Allowance is a pre-paid, subscription-style credit for a single payer
+// address: each request consumes price from the remaining balance
+// instead of triggering a fresh on-chain settlement, until the balance
+// runs out.
+type Allowance struct {
+	mu        sync.Mutex
+	address   string
+	remaining *big.Int
+}
+
+// NewAllowance creates an Allowance of remaining atomic units for address,
+// typically funded out of band (e.g. a single larger upfront payment).
+func NewAllowance(address string, remaining *big.Int) *Allowance {
+	return &Allowance{address: strings.ToLower(address), remaining: new(big.Int).Set(remaining)}
+}
+
+// Remaining reports the current balance.
+func (a *Allowance) Remaining() *big.Int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return new(big.Int).Set(a.remaining)
+}
+
+// tryConsume decrements the allowance by cost if address matches and
+// enough balance remains, reporting whether it did.
+func (a *Allowance) tryConsume(address string, cost *big.Int) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if !strings.EqualFold(address, a.address) {
+		return false
+	}
+	if a.remaining.Cmp(cost) < 0 {
+		return false
+	}
+	a.remaining.Sub(a.remaining, cost)
+	return true
+}
+
+// WithAllowance skips on-chain settlement for requests paid from
+// allowance's address, decrementing its balance instead, until it is
+// exhausted (after which requests fall back to normal per-call
+// verify+settle). Build allowance with NewAllowance.
+func WithAllowance(allowance *Allowance) Option {
+	return func(c *config) { c.allowance = allowance }
+}
@@ -0,0 +1,196 @@
+package x402gin
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/coinbase/x402/go/pkg/types"
+)
+
+// stubProvider is a FacilitatorProvider test double that always approves
+// Verify and records the PaymentRequirements it was asked to Settle.
+type stubProvider struct {
+	settleRequirements []*types.PaymentRequirements
+}
+
+func (s *stubProvider) Verify(*types.PaymentPayload, *types.PaymentRequirements) (*types.VerifyResponse, error) {
+	return &types.VerifyResponse{IsValid: true}, nil
+}
+
+func (s *stubProvider) Settle(_ *types.PaymentPayload, requirements *types.PaymentRequirements) (*types.SettleResponse, error) {
+	s.settleRequirements = append(s.settleRequirements, requirements)
+	return &types.SettleResponse{Success: true}, nil
+}
+
+func (s *stubProvider) Supported(context.Context) (*types.SupportedResponse, error) {
+	return &types.SupportedResponse{}, nil
+}
+
+func newPaidRequest(t *testing.T) *http.Request {
+	t.Helper()
+	payload := types.PaymentPayload{
+		X402Version: 1,
+		Scheme:      "exact",
+		Network:     "bsc-mainnet",
+		Payload: &types.ExactEvmPayload{
+			Signature: "0xsig",
+			Authorization: &types.ExactEvmPayloadAuthorization{
+				From:        "0xpayer",
+				To:          "0xmerchant",
+				Value:       "1000000",
+				ValidAfter:  "0",
+				ValidBefore: "9999999999",
+				Nonce:       "0xnonce",
+			},
+		},
+	}
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatalf("marshal payload: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(paymentHeader, base64.StdEncoding.EncodeToString(raw))
+	return req
+}
+
+func TestMeteredPriceSettlesActualUsageNotReservation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	provider := &stubProvider{}
+
+	r := gin.New()
+	r.GET("/", PaymentMiddleware(
+		big.NewFloat(0), "0xmerchant",
+		WithFacilitatorProvider(provider),
+		WithMeteredPrice(
+			big.NewFloat(0.01),
+			func(*gin.Context) uint64 { return 100 }, // reserve for 100 units
+			func(*gin.Context) uint64 { return 7 },   // handler only used 7
+		),
+	), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, newPaidRequest(t))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if len(provider.settleRequirements) != 1 {
+		t.Fatalf("expected exactly one Settle call, got %d", len(provider.settleRequirements))
+	}
+
+	got := provider.settleRequirements[0].MaxAmountRequired
+	want := usdToAtomicUnits(big.NewFloat(0.07), usdcDecimals) // 7 units * $0.01
+	if got != want {
+		t.Errorf("expected metered settlement of %s (actual usage), got %s (would be the %d-unit reservation)", want, got, 100)
+	}
+}
+
+func TestMeteredPriceHonorsUnitsConsumedHeader(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	provider := &stubProvider{}
+
+	r := gin.New()
+	r.GET("/", PaymentMiddleware(
+		big.NewFloat(0), "0xmerchant",
+		WithFacilitatorProvider(provider),
+		WithMeteredPrice(
+			big.NewFloat(0.01),
+			func(*gin.Context) uint64 { return 100 },
+			func(*gin.Context) uint64 { t.Fatal("finalizeFn should not run when the header is set"); return 0 },
+		),
+	), func(c *gin.Context) {
+		// Simulates a streamed handler that reports usage as it flushes
+		// chunks, rather than all at once at the end.
+		c.Writer.Header().Set(unitsConsumedHeader, "42")
+		c.Writer.WriteHeader(http.StatusOK)
+		c.Writer.Write([]byte("chunk-1"))
+		c.Writer.Flush()
+		c.Writer.Write([]byte("chunk-2"))
+	})
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, newPaidRequest(t))
+
+	if len(provider.settleRequirements) != 1 {
+		t.Fatalf("expected exactly one Settle call, got %d", len(provider.settleRequirements))
+	}
+	got := provider.settleRequirements[0].MaxAmountRequired
+	want := usdToAtomicUnits(big.NewFloat(0.42), usdcDecimals)
+	if got != want {
+		t.Errorf("expected settlement of %s, got %s", want, got)
+	}
+	if body := rec.Body.String(); body != "chunk-1chunk-2" {
+		t.Errorf("expected streamed body to reach the client unmodified, got %q", body)
+	}
+}
+
+func TestAllowanceSkipsSettleUntilExhausted(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	provider := &stubProvider{}
+	allowance := NewAllowance("0xpayer", big.NewInt(1_500_000)) // 1.5 USDC pre-paid
+
+	r := gin.New()
+	r.GET("/", PaymentMiddleware(
+		big.NewFloat(1), "0xmerchant",
+		WithFacilitatorProvider(provider),
+		WithAllowance(allowance),
+	), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	// Price is $1 = 1_000_000 atomic units; the allowance covers one call.
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, newPaidRequest(t))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if len(provider.settleRequirements) != 0 {
+		t.Fatalf("expected the first call to be covered by the allowance with no Settle, got %d Settle calls", len(provider.settleRequirements))
+	}
+	if remaining := allowance.Remaining(); remaining.Cmp(big.NewInt(500_000)) != 0 {
+		t.Errorf("expected 500000 remaining after one $1 call, got %s", remaining)
+	}
+
+	// The allowance only has 0.5 USDC left, less than the $1 price, so the
+	// next call must fall back to a real on-chain settlement.
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, newPaidRequest(t))
+	if len(provider.settleRequirements) != 1 {
+		t.Fatalf("expected the second call to fall back to Settle once the allowance is insufficient, got %d Settle calls", len(provider.settleRequirements))
+	}
+}
+
+func TestDynamicPriceOverridesStaticPrice(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	provider := &stubProvider{}
+
+	r := gin.New()
+	r.GET("/", PaymentMiddleware(
+		big.NewFloat(1), "0xmerchant",
+		WithFacilitatorProvider(provider),
+		WithDynamicPrice(func(c *gin.Context) *big.Float { return big.NewFloat(0.25) }),
+	), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, newPaidRequest(t))
+
+	if len(provider.settleRequirements) != 1 {
+		t.Fatalf("expected exactly one Settle call, got %d", len(provider.settleRequirements))
+	}
+	got := provider.settleRequirements[0].MaxAmountRequired
+	want := usdToAtomicUnits(big.NewFloat(0.25), usdcDecimals)
+	if got != want {
+		t.Errorf("expected dynamic price of %s, got %s", want, got)
+	}
+}
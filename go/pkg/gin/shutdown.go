@@ -0,0 +1,212 @@
+package x402gin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/coinbase/x402/go/pkg/types"
+)
+
+// PendingSettlement is a Settle call that was in flight when shutdown was
+// requested, captured with everything needed to retry it on next boot.
+type PendingSettlement struct {
+	X402Version  int                        `json:"x402Version"`
+	Scheme       string                     `json:"scheme"`
+	Network      string                     `json:"network"`
+	Payload      *types.ExactEvmPayload     `json:"payload"`
+	Requirements *types.PaymentRequirements `json:"requirements"`
+}
+
+// PaymentPayload reassembles the types.PaymentPayload this settlement was
+// originally built from, for replaying it against a FacilitatorProvider.
+func (p *PendingSettlement) PaymentPayload() *types.PaymentPayload {
+	return &types.PaymentPayload{
+		X402Version: p.X402Version,
+		Scheme:      p.Scheme,
+		Network:     p.Network,
+		Payload:     p.Payload,
+	}
+}
+
+// RetryQueue persists PendingSettlements that didn't finish before the
+// shutdown grace period elapsed, so an operator's boot sequence can replay
+// them against the facilitator instead of silently losing them.
+type RetryQueue interface {
+	Record(PendingSettlement) error
+	Load() ([]PendingSettlement, error)
+	Clear() error
+}
+
+// SettlementTracker counts Settle calls in flight so graceful shutdown can
+// wait for them to finish instead of cutting them off mid-transfer. Settle
+// calls still pending when its Drain deadline passes are persisted to the
+// configured RetryQueue, if any.
+type SettlementTracker struct {
+	mu      sync.Mutex
+	nextID  uint64
+	pending map[uint64]PendingSettlement
+	queue   RetryQueue
+}
+
+// NewSettlementTracker creates a SettlementTracker. queue may be nil, in
+// which case settlements still pending at the end of the grace period are
+// simply dropped, matching the tracker's behavior before a queue existed.
+func NewSettlementTracker(queue RetryQueue) *SettlementTracker {
+	return &SettlementTracker{pending: make(map[uint64]PendingSettlement), queue: queue}
+}
+
+// begin records that a Settle call for payload/requirements is starting,
+// returning a handle to pass to end once it finishes.
+func (t *SettlementTracker) begin(payload *types.PaymentPayload, requirements *types.PaymentRequirements) uint64 {
+	exact, _ := payload.Payload.(*types.ExactEvmPayload)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.nextID++
+	id := t.nextID
+	t.pending[id] = PendingSettlement{
+		X402Version:  payload.X402Version,
+		Scheme:       payload.Scheme,
+		Network:      payload.Network,
+		Payload:      exact,
+		Requirements: requirements,
+	}
+	return id
+}
+
+// end records that the Settle call identified by id has finished.
+func (t *SettlementTracker) end(id uint64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.pending, id)
+}
+
+// Pending reports how many Settle calls are currently in flight.
+func (t *SettlementTracker) Pending() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.pending)
+}
+
+// Drain blocks until no Settle calls are in flight or ctx is done. If ctx
+// is done first, every still-pending settlement is written to the
+// tracker's RetryQueue (when configured) and Drain returns ctx.Err(), or
+// the RetryQueue's error if recording a settlement fails.
+func (t *SettlementTracker) Drain(ctx context.Context) error {
+	ticker := time.NewTicker(20 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		if t.Pending() == 0 {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return t.persistPending(ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+func (t *SettlementTracker) persistPending(cause error) error {
+	t.mu.Lock()
+	remaining := make([]PendingSettlement, 0, len(t.pending))
+	for _, s := range t.pending {
+		remaining = append(remaining, s)
+	}
+	t.mu.Unlock()
+
+	if t.queue == nil || len(remaining) == 0 {
+		return cause
+	}
+	for _, s := range remaining {
+		if err := t.queue.Record(s); err != nil {
+			return err
+		}
+	}
+	return cause
+}
+
+// WithSettlementTracker wires a SettlementTracker into the middleware so
+// graceful shutdown can observe and wait for Settle calls it starts.
+func WithSettlementTracker(tracker *SettlementTracker) Option {
+	return func(c *config) { c.settlementTracker = tracker }
+}
+
+// FileRetryQueue is a RetryQueue backed by a newline-delimited JSON file,
+// suitable for a single-replica deployment where the same disk is read on
+// the next boot.
+type FileRetryQueue struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewFileRetryQueue creates a FileRetryQueue persisting to path. The file
+// is created on first Record; it need not exist beforehand.
+func NewFileRetryQueue(path string) *FileRetryQueue {
+	return &FileRetryQueue{path: path}
+}
+
+// Record appends entry to the queue file.
+func (q *FileRetryQueue) Record(entry PendingSettlement) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(q.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(append(line, '\n'))
+	return err
+}
+
+// Load reads every entry recorded since the queue was last Cleared. A
+// missing file is treated as an empty queue rather than an error.
+func (q *FileRetryQueue) Load() ([]PendingSettlement, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	data, err := os.ReadFile(q.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []PendingSettlement
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var entry PendingSettlement
+		if err := json.Unmarshal(line, &entry); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// Clear removes the queue file, typically called once every loaded entry
+// has been successfully replayed.
+func (q *FileRetryQueue) Clear() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	err := os.Remove(q.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,154 @@
+package x402gin
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/coinbase/x402/go/pkg/types"
+)
+
+func testPayload() *types.PaymentPayload {
+	return &types.PaymentPayload{
+		X402Version: 1,
+		Scheme:      "exact",
+		Network:     "bsc-mainnet",
+		Payload: &types.ExactEvmPayload{
+			Signature: "0xsig",
+			Authorization: &types.ExactEvmPayloadAuthorization{
+				From: "0xpayer", To: "0xmerchant", Value: "1000000",
+				ValidAfter: "0", ValidBefore: "9999999999", Nonce: "0xnonce",
+			},
+		},
+	}
+}
+
+func TestSettlementTrackerDrainReturnsImmediatelyWhenIdle(t *testing.T) {
+	tracker := NewSettlementTracker(nil)
+	if err := tracker.Drain(context.Background()); err != nil {
+		t.Fatalf("expected idle Drain to succeed, got %v", err)
+	}
+}
+
+func TestSettlementTrackerDrainWaitsForInFlightSettle(t *testing.T) {
+	tracker := NewSettlementTracker(nil)
+	id := tracker.begin(testPayload(), &types.PaymentRequirements{})
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		tracker.end(id)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := tracker.Drain(ctx); err != nil {
+		t.Fatalf("expected Drain to wait for the settlement and succeed, got %v", err)
+	}
+	if tracker.Pending() != 0 {
+		t.Errorf("expected no pending settlements after Drain, got %d", tracker.Pending())
+	}
+}
+
+func TestSettlementTrackerDrainPersistsToRetryQueueOnTimeout(t *testing.T) {
+	queue := NewFileRetryQueue(filepath.Join(t.TempDir(), "retries.jsonl"))
+	tracker := NewSettlementTracker(queue)
+	requirements := &types.PaymentRequirements{MaxAmountRequired: "1000000", PayTo: "0xmerchant"}
+	tracker.begin(testPayload(), requirements)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := tracker.Drain(ctx); err == nil {
+		t.Fatal("expected Drain to return the context's deadline error")
+	}
+
+	entries, err := queue.Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 persisted settlement, got %d", len(entries))
+	}
+	if entries[0].Requirements.MaxAmountRequired != "1000000" {
+		t.Errorf("expected persisted requirements to round-trip, got %+v", entries[0].Requirements)
+	}
+	if entries[0].Payload.Authorization.From != "0xpayer" {
+		t.Errorf("expected persisted payload to round-trip, got %+v", entries[0].Payload)
+	}
+}
+
+func TestFileRetryQueueLoadOfMissingFileIsEmpty(t *testing.T) {
+	queue := NewFileRetryQueue(filepath.Join(t.TempDir(), "does-not-exist.jsonl"))
+	entries, err := queue.Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("expected no entries, got %d", len(entries))
+	}
+}
+
+func TestPaymentMiddlewareTracksSettlementAroundProviderCall(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	provider := &stubProvider{}
+	tracker := NewSettlementTracker(nil)
+
+	r := gin.New()
+	r.GET("/", PaymentMiddleware(
+		big.NewFloat(1), "0xmerchant",
+		WithFacilitatorProvider(provider),
+		WithSettlementTracker(tracker),
+	), func(c *gin.Context) {
+		if tracker.Pending() != 0 {
+			t.Errorf("expected no pending settlement while the handler is running, got %d", tracker.Pending())
+		}
+		c.Status(http.StatusOK)
+	})
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, newPaidRequest(t))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if len(provider.settleRequirements) != 1 {
+		t.Fatalf("expected exactly one Settle call, got %d", len(provider.settleRequirements))
+	}
+	if tracker.Pending() != 0 {
+		t.Errorf("expected the tracker to be empty once Settle returned, got %d pending", tracker.Pending())
+	}
+}
+
+func TestFileRetryQueueRecordLoadClear(t *testing.T) {
+	queue := NewFileRetryQueue(filepath.Join(t.TempDir(), "retries.jsonl"))
+
+	for i := 0; i < 3; i++ {
+		if err := queue.Record(PendingSettlement{Network: "bsc-mainnet"}); err != nil {
+			t.Fatalf("Record: %v", err)
+		}
+	}
+
+	entries, err := queue.Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(entries))
+	}
+
+	if err := queue.Clear(); err != nil {
+		t.Fatalf("Clear: %v", err)
+	}
+	entries, err = queue.Load()
+	if err != nil {
+		t.Fatalf("Load after Clear: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("expected no entries after Clear, got %d", len(entries))
+	}
+}
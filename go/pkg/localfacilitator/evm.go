@@ -0,0 +1,250 @@
+package localfacilitator
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/coinbase/x402/go/pkg/types"
+)
+
+// transferWithAuthorizationABIJSON describes the EIP-3009
+// transferWithAuthorization function shared by USDC and compatible assets.
+const transferWithAuthorizationABIJSON = `[{
+	"name": "transferWithAuthorization",
+	"type": "function",
+	"stateMutability": "nonpayable",
+	"inputs": [
+		{"name": "from", "type": "address"},
+		{"name": "to", "type": "address"},
+		{"name": "value", "type": "uint256"},
+		{"name": "validAfter", "type": "uint256"},
+		{"name": "validBefore", "type": "uint256"},
+		{"name": "nonce", "type": "bytes32"},
+		{"name": "v", "type": "uint8"},
+		{"name": "r", "type": "bytes32"},
+		{"name": "s", "type": "bytes32"}
+	],
+	"outputs": []
+}]`
+
+const defaultSettleGasLimit = 120_000
+
+// eip712TokenName and eip712TokenVersion are the EIP-712 domain name and
+// version USDC (and compatible EIP-3009 tokens sharing its contract
+// lineage) sign TransferWithAuthorization messages under. A token with a
+// different domain would need its own digest function.
+const (
+	eip712TokenName    = "USD Coin"
+	eip712TokenVersion = "2"
+)
+
+var (
+	// eip712DomainTypeHash and transferWithAuthorizationTypeHash are the
+	// EIP-712 type hashes for EIP712Domain and EIP-3009's
+	// TransferWithAuthorization struct, per
+	// https://eips.ethereum.org/EIPS/eip-3009.
+	eip712DomainTypeHash              = crypto.Keccak256([]byte("EIP712Domain(string name,string version,uint256 chainId,address verifyingContract)"))
+	transferWithAuthorizationTypeHash = crypto.Keccak256([]byte("TransferWithAuthorization(address from,address to,uint256 value,uint256 validAfter,uint256 validBefore,bytes32 nonce)"))
+)
+
+// networkChainID maps the networks this package knows how to settle on to
+// their EVM chain ID, which EIP-712 domain-separates a signature so it
+// can't be replayed on a different chain.
+func networkChainID(network string) (*big.Int, error) {
+	switch network {
+	case "bsc-mainnet":
+		return big.NewInt(56), nil
+	case "bsc-testnet":
+		return big.NewInt(97), nil
+	default:
+		return nil, fmt.Errorf("localfacilitator: unknown network %q, don't know its EIP-712 chain id", network)
+	}
+}
+
+func exactEvmFields(payload *types.PaymentPayload) (*types.ExactEvmPayloadAuthorization, []byte, error) {
+	if payload.Scheme != "exact" {
+		return nil, nil, fmt.Errorf("localfacilitator: unsupported scheme %q", payload.Scheme)
+	}
+	exact, ok := payload.Payload.(*types.ExactEvmPayload)
+	if !ok {
+		return nil, nil, fmt.Errorf("localfacilitator: payload is not an ExactEvmPayload")
+	}
+	if exact.Authorization == nil {
+		return nil, nil, fmt.Errorf("localfacilitator: missing authorization")
+	}
+	sig, err := decodeHex(exact.Signature)
+	if err != nil {
+		return nil, nil, fmt.Errorf("localfacilitator: decoding signature: %w", err)
+	}
+	if len(sig) != 65 {
+		return nil, nil, fmt.Errorf("localfacilitator: signature must be 65 bytes, got %d", len(sig))
+	}
+	return exact.Authorization, sig, nil
+}
+
+func decodeHex(s string) ([]byte, error) {
+	return hex.DecodeString(strings.TrimPrefix(s, "0x"))
+}
+
+// authorizationDigest computes the EIP-712 digest a payer signs over an
+// EIP-3009 TransferWithAuthorization message:
+//
+//	keccak256("\x19\x01" || domainSeparator || structHash)
+//
+// per https://eips.ethereum.org/EIPS/eip-712 and
+// https://eips.ethereum.org/EIPS/eip-3009. domainSeparator binds the
+// signature to this asset contract (verifyingContract) and chain
+// (chainId), so it can't be replayed against a different token or
+// network.
+func authorizationDigest(asset, network string, auth *types.ExactEvmPayloadAuthorization) ([32]byte, error) {
+	chainID, err := networkChainID(network)
+	if err != nil {
+		return [32]byte{}, err
+	}
+
+	domainSeparator := crypto.Keccak256(
+		eip712DomainTypeHash,
+		crypto.Keccak256([]byte(eip712TokenName)),
+		crypto.Keccak256([]byte(eip712TokenVersion)),
+		common.LeftPadBytes(chainID.Bytes(), 32),
+		common.LeftPadBytes(common.HexToAddress(asset).Bytes(), 32),
+	)
+	structHash := crypto.Keccak256(
+		transferWithAuthorizationTypeHash,
+		common.LeftPadBytes(common.HexToAddress(auth.From).Bytes(), 32),
+		common.LeftPadBytes(common.HexToAddress(auth.To).Bytes(), 32),
+		leftPadHex(auth.Value),
+		leftPadHex(auth.ValidAfter),
+		leftPadHex(auth.ValidBefore),
+		common.HexToHash(auth.Nonce).Bytes(),
+	)
+
+	var digest [32]byte
+	copy(digest[:], crypto.Keccak256([]byte{0x19, 0x01}, domainSeparator, structHash))
+	return digest, nil
+}
+
+func leftPadHex(decimal string) []byte {
+	n, ok := new(big.Int).SetString(decimal, 10)
+	if !ok {
+		n = big.NewInt(0)
+	}
+	return common.LeftPadBytes(n.Bytes(), 32)
+}
+
+func recoverSigner(digest [32]byte, sig []byte) (string, error) {
+	sigCopy := make([]byte, len(sig))
+	copy(sigCopy, sig)
+	// go-ethereum's Ecrecover expects the recovery id in the last byte as
+	// 0/1; EIP-3009 signatures are conventionally encoded with v as 27/28.
+	if sigCopy[64] >= 27 {
+		sigCopy[64] -= 27
+	}
+	pub, err := crypto.SigToPub(digest[:], sigCopy)
+	if err != nil {
+		return "", err
+	}
+	return crypto.PubkeyToAddress(*pub).Hex(), nil
+}
+
+func addressesEqual(a, b string) bool {
+	return common.HexToAddress(a) == common.HexToAddress(b)
+}
+
+func parseValidityWindow(auth *types.ExactEvmPayloadAuthorization) (int64, int64, error) {
+	validAfter, err := strconv.ParseInt(auth.ValidAfter, 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("localfacilitator: invalid validAfter: %w", err)
+	}
+	validBefore, err := strconv.ParseInt(auth.ValidBefore, 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("localfacilitator: invalid validBefore: %w", err)
+	}
+	return validAfter, validBefore, nil
+}
+
+// submitTransferWithAuthorization broadcasts the payer's already-signed
+// EIP-3009 authorization to the asset contract. The settling transaction
+// itself is signed through signer, which is the extension point custodial
+// key stores (vaultfacilitator) hook into.
+func submitTransferWithAuthorization(ctx context.Context, rpcURL string, signer TxSigner, asset string, auth *types.ExactEvmPayloadAuthorization, sig []byte) (string, error) {
+	client, err := ethclient.DialContext(ctx, rpcURL)
+	if err != nil {
+		return "", fmt.Errorf("dialing RPC: %w", err)
+	}
+	defer client.Close()
+
+	parsedABI, err := abi.JSON(strings.NewReader(transferWithAuthorizationABIJSON))
+	if err != nil {
+		return "", fmt.Errorf("parsing ABI: %w", err)
+	}
+
+	value, _ := new(big.Int).SetString(auth.Value, 10)
+	validAfter, _ := new(big.Int).SetString(auth.ValidAfter, 10)
+	validBefore, _ := new(big.Int).SetString(auth.ValidBefore, 10)
+
+	data, err := parsedABI.Pack("transferWithAuthorization",
+		common.HexToAddress(auth.From),
+		common.HexToAddress(auth.To),
+		value,
+		validAfter,
+		validBefore,
+		common.HexToHash(auth.Nonce),
+		sig[64],
+		common.BytesToHash(sig[:32]),
+		common.BytesToHash(sig[32:64]),
+	)
+	if err != nil {
+		return "", fmt.Errorf("packing call data: %w", err)
+	}
+
+	settlerAddr := common.HexToAddress(signer.Address())
+	nonce, err := client.PendingNonceAt(ctx, settlerAddr)
+	if err != nil {
+		return "", fmt.Errorf("fetching settler nonce: %w", err)
+	}
+	gasPrice, err := client.SuggestGasPrice(ctx)
+	if err != nil {
+		return "", fmt.Errorf("fetching gas price: %w", err)
+	}
+	chainID, err := client.NetworkID(ctx)
+	if err != nil {
+		return "", fmt.Errorf("fetching chain id: %w", err)
+	}
+
+	assetAddr := common.HexToAddress(asset)
+	tx := gethtypes.NewTx(&gethtypes.LegacyTx{
+		Nonce:    nonce,
+		To:       &assetAddr,
+		Value:    big.NewInt(0),
+		Gas:      defaultSettleGasLimit,
+		GasPrice: gasPrice,
+		Data:     data,
+	})
+
+	txSigner := gethtypes.NewEIP155Signer(chainID)
+	txDigest := txSigner.Hash(tx)
+	txSig, err := signer.SignDigest(ctx, [32]byte(txDigest))
+	if err != nil {
+		return "", fmt.Errorf("signing settlement tx: %w", err)
+	}
+	signedTx, err := tx.WithSignature(txSigner, txSig)
+	if err != nil {
+		return "", fmt.Errorf("applying settlement signature: %w", err)
+	}
+
+	if err := client.SendTransaction(ctx, signedTx); err != nil {
+		return "", fmt.Errorf("broadcasting settlement tx: %w", err)
+	}
+	return signedTx.Hash().Hex(), nil
+}
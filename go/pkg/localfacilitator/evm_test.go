@@ -0,0 +1,114 @@
+package localfacilitator
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/coinbase/x402/go/pkg/types"
+)
+
+// referenceDigest independently re-derives the EIP-712 digest straight
+// from the EIP-3009 spec, without going through authorizationDigest's own
+// helpers, so a regression that breaks the real encoding (e.g. dropping
+// the chain id from the domain, or hashing fields in the wrong order)
+// shows up as a mismatch here instead of silently passing because both
+// sides share the same bug.
+func referenceDigest(t *testing.T, asset, network string, auth *types.ExactEvmPayloadAuthorization) [32]byte {
+	t.Helper()
+
+	chainID, ok := map[string]int64{"bsc-mainnet": 56, "bsc-testnet": 97}[network]
+	if !ok {
+		t.Fatalf("no reference chain id for network %q", network)
+	}
+
+	domainTypeHash := crypto.Keccak256([]byte("EIP712Domain(string name,string version,uint256 chainId,address verifyingContract)"))
+	domainSeparator := crypto.Keccak256(
+		domainTypeHash,
+		crypto.Keccak256([]byte("USD Coin")),
+		crypto.Keccak256([]byte("2")),
+		common.LeftPadBytes(big.NewInt(chainID).Bytes(), 32),
+		common.LeftPadBytes(common.HexToAddress(asset).Bytes(), 32),
+	)
+
+	authTypeHash := crypto.Keccak256([]byte("TransferWithAuthorization(address from,address to,uint256 value,uint256 validAfter,uint256 validBefore,bytes32 nonce)"))
+	value, _ := new(big.Int).SetString(auth.Value, 10)
+	validAfter, _ := new(big.Int).SetString(auth.ValidAfter, 10)
+	validBefore, _ := new(big.Int).SetString(auth.ValidBefore, 10)
+	structHash := crypto.Keccak256(
+		authTypeHash,
+		common.LeftPadBytes(common.HexToAddress(auth.From).Bytes(), 32),
+		common.LeftPadBytes(common.HexToAddress(auth.To).Bytes(), 32),
+		common.LeftPadBytes(value.Bytes(), 32),
+		common.LeftPadBytes(validAfter.Bytes(), 32),
+		common.LeftPadBytes(validBefore.Bytes(), 32),
+		common.HexToHash(auth.Nonce).Bytes(),
+	)
+
+	var digest [32]byte
+	copy(digest[:], crypto.Keccak256([]byte{0x19, 0x01}, domainSeparator, structHash))
+	return digest
+}
+
+func TestAuthorizationDigestMatchesEIP712Spec(t *testing.T) {
+	const asset = "0x0000000000000000000000000000000000dead"
+	const network = "bsc-mainnet"
+	auth := &types.ExactEvmPayloadAuthorization{
+		From:        "0x00000000000000000000000000000000000a11",
+		To:          "0x00000000000000000000000000000000000b0b",
+		Value:       "1000000",
+		ValidAfter:  "0",
+		ValidBefore: "9999999999",
+		Nonce:       "0x0000000000000000000000000000000000000000000000000000000000000001",
+	}
+
+	got, err := authorizationDigest(asset, network, auth)
+	if err != nil {
+		t.Fatalf("authorizationDigest returned error: %v", err)
+	}
+	want := referenceDigest(t, asset, network, auth)
+	if got != want {
+		t.Errorf("authorizationDigest = %x, want %x (independently re-derived per EIP-3009)", got, want)
+	}
+}
+
+func TestAuthorizationDigestBindsChainAndAsset(t *testing.T) {
+	const asset = "0x0000000000000000000000000000000000dead"
+	auth := &types.ExactEvmPayloadAuthorization{
+		From:        "0x00000000000000000000000000000000000a11",
+		To:          "0x00000000000000000000000000000000000b0b",
+		Value:       "1000000",
+		ValidAfter:  "0",
+		ValidBefore: "9999999999",
+		Nonce:       "0x0000000000000000000000000000000000000000000000000000000000000001",
+	}
+
+	base, err := authorizationDigest(asset, "bsc-mainnet", auth)
+	if err != nil {
+		t.Fatalf("authorizationDigest returned error: %v", err)
+	}
+	otherChain, err := authorizationDigest(asset, "bsc-testnet", auth)
+	if err != nil {
+		t.Fatalf("authorizationDigest returned error: %v", err)
+	}
+	if base == otherChain {
+		t.Error("digest did not change across networks; a signature could be replayed cross-chain")
+	}
+
+	otherAsset, err := authorizationDigest("0x0000000000000000000000000000000000beef", "bsc-mainnet", auth)
+	if err != nil {
+		t.Fatalf("authorizationDigest returned error: %v", err)
+	}
+	if base == otherAsset {
+		t.Error("digest did not change across assets; a signature could be replayed against a different token")
+	}
+}
+
+func TestAuthorizationDigestUnknownNetwork(t *testing.T) {
+	auth := &types.ExactEvmPayloadAuthorization{Value: "0", ValidAfter: "0", ValidBefore: "0", Nonce: "0x0"}
+	if _, err := authorizationDigest("0x0000000000000000000000000000000000dead", "unknown-network", auth); err == nil {
+		t.Error("expected an error for an unrecognized network, got nil")
+	}
+}
@@ -0,0 +1,195 @@
+// Package localfacilitator implements a FacilitatorProvider that verifies
+// and settles EIP-3009 "exact" payments in-process, without proxying to a
+// remote facilitator. It is the zero-config default: an operator only
+// needs an RPC endpoint and a settling private key.
+package localfacilitator
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/coinbase/x402/go/pkg/facilitatorclient"
+	"github.com/coinbase/x402/go/pkg/types"
+)
+
+// Config configures the on-chain side of the local facilitator: where to
+// reach the chain, and which network it is expected to be.
+type Config struct {
+	RPCURL  string
+	Network string
+}
+
+// TxSigner abstracts over where the settling private key lives, so the
+// verify/settle logic in Provider can be reused by custodial key stores
+// (e.g. vaultfacilitator, which signs via HashiCorp Vault's Transit
+// engine instead of holding the key in process memory).
+type TxSigner interface {
+	// Address is the settling account that submits transferWithAuthorization
+	// and pays gas for it.
+	Address() string
+	// SignDigest signs a 32-byte hash and returns the 65-byte
+	// (R || S || V) recoverable signature go-ethereum's tx.WithSignature
+	// expects.
+	SignDigest(ctx context.Context, digest [32]byte) ([]byte, error)
+}
+
+// Provider is a FacilitatorProvider that verifies EIP-3009 authorization
+// signatures locally and settles them by submitting
+// transferWithAuthorization directly to the token contract.
+type Provider struct {
+	cfg    Config
+	signer TxSigner
+}
+
+// NewProvider builds a local Provider from an explicit config and signer.
+// Use this when wiring a custom signer (e.g. from vaultfacilitator); use
+// NewProviderFromEnv for the common raw-private-key case.
+func NewProvider(cfg Config, signer TxSigner) *Provider {
+	return &Provider{cfg: cfg, signer: signer}
+}
+
+// NewProviderFromEnv builds a local Provider reading RPC_URL, EVM_NETWORK,
+// and SETTLER_PRIVATE_KEY from the environment.
+func NewProviderFromEnv() (facilitatorclient.FacilitatorProvider, error) {
+	rpcURL := os.Getenv("RPC_URL")
+	network := os.Getenv("EVM_NETWORK")
+	if network == "" {
+		network = "bsc-mainnet"
+	}
+	keyHex := os.Getenv("SETTLER_PRIVATE_KEY")
+	if rpcURL == "" || keyHex == "" {
+		return nil, fmt.Errorf("localfacilitator: FACILITATOR_PROVIDER=local requires RPC_URL and SETTLER_PRIVATE_KEY")
+	}
+	signer, err := newRawKeySigner(keyHex)
+	if err != nil {
+		return nil, err
+	}
+	return NewProvider(Config{RPCURL: rpcURL, Network: network}, signer), nil
+}
+
+// Verify recovers the signer of the EIP-3009 authorization and checks it
+// against PaymentRequirements and the authorization's own validity window.
+func (p *Provider) Verify(paymentPayload *types.PaymentPayload, paymentRequirements *types.PaymentRequirements) (*types.VerifyResponse, error) {
+	auth, sig, err := exactEvmFields(paymentPayload)
+	if err != nil {
+		return &types.VerifyResponse{IsValid: false, InvalidReason: err.Error()}, nil
+	}
+
+	digest, err := authorizationDigest(paymentRequirements.Asset, paymentRequirements.Network, auth)
+	if err != nil {
+		return &types.VerifyResponse{IsValid: false, InvalidReason: err.Error()}, nil
+	}
+	recovered, err := recoverSigner(digest, sig)
+	if err != nil {
+		return &types.VerifyResponse{IsValid: false, InvalidReason: fmt.Sprintf("invalid signature: %v", err)}, nil
+	}
+	if !addressesEqual(recovered, auth.From) {
+		return &types.VerifyResponse{IsValid: false, InvalidReason: "signature does not match authorization.from"}, nil
+	}
+
+	validAfter, validBefore, err := parseValidityWindow(auth)
+	if err != nil {
+		return &types.VerifyResponse{IsValid: false, InvalidReason: err.Error()}, nil
+	}
+	now := time.Now().Unix()
+	if now < validAfter || now > validBefore {
+		return &types.VerifyResponse{IsValid: false, InvalidReason: "authorization outside its validity window"}, nil
+	}
+	if !addressesEqual(auth.To, paymentRequirements.PayTo) {
+		return &types.VerifyResponse{IsValid: false, InvalidReason: "authorization.to does not match payTo"}, nil
+	}
+
+	value, ok := new(big.Int).SetString(auth.Value, 10)
+	if !ok {
+		return &types.VerifyResponse{IsValid: false, InvalidReason: "authorization.value is not a valid integer"}, nil
+	}
+	required, ok := new(big.Int).SetString(paymentRequirements.MaxAmountRequired, 10)
+	if !ok {
+		return &types.VerifyResponse{IsValid: false, InvalidReason: "maxAmountRequired is not a valid integer"}, nil
+	}
+	if value.Cmp(required) < 0 {
+		return &types.VerifyResponse{IsValid: false, InvalidReason: "authorization.value is less than maxAmountRequired"}, nil
+	}
+
+	return &types.VerifyResponse{IsValid: true}, nil
+}
+
+// Settle submits the already-signed EIP-3009 authorization to the asset
+// contract's transferWithAuthorization function, paying gas from the
+// settling account held by p.signer.
+//
+// transferWithAuthorization's on-chain signature check covers the exact
+// value the payer signed, so this provider can only ever settle for that
+// full authorized value — it has no way to honor a paymentRequirements
+// asking for less (e.g. x402gin's metered pricing settling actual usage
+// below the reserved estimate) without a fresh authorization from the
+// payer. Rather than silently settle the full value while the caller
+// believes a smaller amount was charged, Settle rejects that case.
+func (p *Provider) Settle(paymentPayload *types.PaymentPayload, paymentRequirements *types.PaymentRequirements) (*types.SettleResponse, error) {
+	verifyResp, err := p.Verify(paymentPayload, paymentRequirements)
+	if err != nil {
+		return nil, err
+	}
+	if !verifyResp.IsValid {
+		return &types.SettleResponse{Success: false, Network: paymentRequirements.Network, Error: verifyResp.InvalidReason}, nil
+	}
+
+	auth, sig, err := exactEvmFields(paymentPayload)
+	if err != nil {
+		return &types.SettleResponse{Success: false, Network: paymentRequirements.Network, Error: err.Error()}, nil
+	}
+
+	if authorized, ok := new(big.Int).SetString(auth.Value, 10); ok {
+		if required, ok := new(big.Int).SetString(paymentRequirements.MaxAmountRequired, 10); ok && authorized.Cmp(required) > 0 {
+			return &types.SettleResponse{
+				Success: false,
+				Network: paymentRequirements.Network,
+				Error:   fmt.Sprintf("localfacilitator: requested settlement of %s is less than the payer-authorized %s; transferWithAuthorization can't partially redeem a signed authorization, so the payer must sign a fresh authorization for the reduced amount", paymentRequirements.MaxAmountRequired, auth.Value),
+			}, nil
+		}
+	}
+
+	txHash, err := submitTransferWithAuthorization(context.Background(), p.cfg.RPCURL, p.signer, paymentRequirements.Asset, auth, sig)
+	if err != nil {
+		return &types.SettleResponse{Success: false, Network: paymentRequirements.Network, Error: err.Error()}, nil
+	}
+
+	return &types.SettleResponse{Success: true, Transaction: txHash, Network: paymentRequirements.Network}, nil
+}
+
+// Supported reports that this provider handles the "exact" scheme on
+// whatever network it was configured for.
+func (p *Provider) Supported(ctx context.Context) (*types.SupportedResponse, error) {
+	return &types.SupportedResponse{Kinds: []types.SupportedKind{{Scheme: "exact", Network: p.cfg.Network}}}, nil
+}
+
+func init() {
+	facilitatorclient.RegisterProvider("local", NewProviderFromEnv)
+}
+
+// rawKeySigner is the default TxSigner: an ECDSA key held in process
+// memory, supplied via an environment variable.
+type rawKeySigner struct {
+	key  *ecdsa.PrivateKey
+	addr string
+}
+
+func newRawKeySigner(keyHex string) (TxSigner, error) {
+	key, err := crypto.HexToECDSA(keyHex)
+	if err != nil {
+		return nil, fmt.Errorf("localfacilitator: parsing SETTLER_PRIVATE_KEY: %w", err)
+	}
+	return &rawKeySigner{key: key, addr: crypto.PubkeyToAddress(key.PublicKey).Hex()}, nil
+}
+
+func (s *rawKeySigner) Address() string { return s.addr }
+
+func (s *rawKeySigner) SignDigest(ctx context.Context, digest [32]byte) ([]byte, error) {
+	return crypto.Sign(digest[:], s.key)
+}
@@ -0,0 +1,182 @@
+package localfacilitator
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/coinbase/x402/go/pkg/types"
+)
+
+func signAuthorization(t *testing.T, asset, network string, auth *types.ExactEvmPayloadAuthorization) (string, []byte) {
+	t.Helper()
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+	auth.From = crypto.PubkeyToAddress(key.PublicKey).Hex()
+	digest, err := authorizationDigest(asset, network, auth)
+	if err != nil {
+		t.Fatalf("authorizationDigest returned error: %v", err)
+	}
+	sig, err := crypto.Sign(digest[:], key)
+	if err != nil {
+		t.Fatalf("signing digest: %v", err)
+	}
+	sig[64] += 27
+	return auth.From, sig
+}
+
+func TestProviderVerify(t *testing.T) {
+	const asset = "0x0000000000000000000000000000000000dead"
+	const network = "bsc-mainnet"
+	const payTo = "0x00000000000000000000000000000000000b0b"
+
+	baseAuth := func() *types.ExactEvmPayloadAuthorization {
+		return &types.ExactEvmPayloadAuthorization{
+			To:          payTo,
+			Value:       "1000000",
+			ValidAfter:  "0",
+			ValidBefore: "9999999999",
+			Nonce:       "0x0000000000000000000000000000000000000000000000000000000000000001",
+		}
+	}
+
+	reqs := &types.PaymentRequirements{
+		Scheme:            "exact",
+		Network:           network,
+		MaxAmountRequired: "1000000",
+		PayTo:             payTo,
+		Asset:             asset,
+	}
+
+	tests := []struct {
+		name      string
+		mutate    func(auth *types.ExactEvmPayloadAuthorization)
+		wantValid bool
+	}{
+		{
+			name:      "valid authorization",
+			mutate:    func(auth *types.ExactEvmPayloadAuthorization) {},
+			wantValid: true,
+		},
+		{
+			name: "value below requirement",
+			mutate: func(auth *types.ExactEvmPayloadAuthorization) {
+				auth.Value = "1"
+			},
+			wantValid: false,
+		},
+		{
+			name: "expired authorization",
+			mutate: func(auth *types.ExactEvmPayloadAuthorization) {
+				auth.ValidBefore = "1"
+			},
+			wantValid: false,
+		},
+		{
+			name: "wrong recipient",
+			mutate: func(auth *types.ExactEvmPayloadAuthorization) {
+				auth.To = "0x000000000000000000000000000000000000bad"
+			},
+			wantValid: false,
+		},
+	}
+
+	p := NewProvider(Config{Network: network}, nil)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auth := baseAuth()
+			tt.mutate(auth)
+			from, sig := signAuthorization(t, asset, network, auth)
+			auth.From = from
+
+			payload := &types.PaymentPayload{
+				X402Version: 1,
+				Scheme:      "exact",
+				Network:     network,
+				Payload: &types.ExactEvmPayload{
+					Signature:     "0x" + hexEncode(sig),
+					Authorization: auth,
+				},
+			}
+
+			resp, err := p.Verify(payload, reqs)
+			if err != nil {
+				t.Fatalf("Verify returned error: %v", err)
+			}
+			if resp.IsValid != tt.wantValid {
+				t.Errorf("Verify() IsValid = %v (reason %q), want %v", resp.IsValid, resp.InvalidReason, tt.wantValid)
+			}
+		})
+	}
+}
+
+func TestProviderSupported(t *testing.T) {
+	p := NewProvider(Config{Network: "bsc-mainnet"}, nil)
+	resp, err := p.Supported(nil)
+	if err != nil {
+		t.Fatalf("Supported returned error: %v", err)
+	}
+	if len(resp.Kinds) != 1 || resp.Kinds[0].Scheme != "exact" || resp.Kinds[0].Network != "bsc-mainnet" {
+		t.Errorf("unexpected supported kinds: %+v", resp.Kinds)
+	}
+}
+
+func TestProviderSettleRejectsAmountBelowAuthorized(t *testing.T) {
+	const asset = "0x0000000000000000000000000000000000dead"
+	const network = "bsc-mainnet"
+	const payTo = "0x00000000000000000000000000000000000b0b"
+
+	auth := &types.ExactEvmPayloadAuthorization{
+		To:          payTo,
+		Value:       "1000000",
+		ValidAfter:  "0",
+		ValidBefore: "9999999999",
+		Nonce:       "0x0000000000000000000000000000000000000000000000000000000000000002",
+	}
+	from, sig := signAuthorization(t, asset, network, auth)
+	auth.From = from
+
+	payload := &types.PaymentPayload{
+		X402Version: 1,
+		Scheme:      "exact",
+		Network:     network,
+		Payload: &types.ExactEvmPayload{
+			Signature:     "0x" + hexEncode(sig),
+			Authorization: auth,
+		},
+	}
+	// Requesting settlement for less than the signed value, as metered
+	// pricing does once actual usage is known.
+	reqs := &types.PaymentRequirements{
+		Scheme:            "exact",
+		Network:           network,
+		MaxAmountRequired: "7",
+		PayTo:             payTo,
+		Asset:             asset,
+	}
+
+	p := NewProvider(Config{Network: network}, nil)
+	resp, err := p.Settle(payload, reqs)
+	if err != nil {
+		t.Fatalf("Settle returned error: %v", err)
+	}
+	if resp.Success {
+		t.Error("expected Settle to reject a requested amount below the authorized value")
+	}
+	if resp.Error == "" {
+		t.Error("expected Settle to explain why it rejected the request")
+	}
+}
+
+func hexEncode(b []byte) string {
+	const hextable = "0123456789abcdef"
+	out := make([]byte, len(b)*2)
+	for i, v := range b {
+		out[i*2] = hextable[v>>4]
+		out[i*2+1] = hextable[v&0x0f]
+	}
+	return string(out)
+}
@@ -0,0 +1,141 @@
+package noncestore
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// newFakeRedisClient starts an in-process server implementing just enough
+// of the RESP protocol (PING, SET NX, GET, DEL) to exercise Redis-backed
+// code without a real Redis instance.
+func newFakeRedisClient(t *testing.T) *redis.Client {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("starting fake redis listener: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	srv := &fakeRedisServer{data: make(map[string]string)}
+	go srv.serve(ln)
+
+	return redis.NewClient(&redis.Options{Addr: ln.Addr().String()})
+}
+
+type fakeRedisServer struct {
+	mu   sync.Mutex
+	data map[string]string
+}
+
+func (s *fakeRedisServer) serve(ln net.Listener) {
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *fakeRedisServer) handle(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	for {
+		args, err := readRESPArray(r)
+		if err != nil {
+			return
+		}
+		if len(args) == 0 {
+			continue
+		}
+		if _, err := conn.Write([]byte(s.dispatch(args))); err != nil {
+			return
+		}
+	}
+}
+
+func (s *fakeRedisServer) dispatch(args []string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch strings.ToUpper(args[0]) {
+	case "PING":
+		return "+PONG\r\n"
+	case "SET":
+		key, value := args[1], args[2]
+		for _, opt := range args[3:] {
+			if strings.EqualFold(opt, "NX") {
+				if _, exists := s.data[key]; exists {
+					return "$-1\r\n"
+				}
+			}
+		}
+		s.data[key] = value
+		return "+OK\r\n"
+	case "GET":
+		value, ok := s.data[args[1]]
+		if !ok {
+			return "$-1\r\n"
+		}
+		return fmt.Sprintf("$%d\r\n%s\r\n", len(value), value)
+	case "DEL":
+		n := 0
+		for _, key := range args[1:] {
+			if _, ok := s.data[key]; ok {
+				delete(s.data, key)
+				n++
+			}
+		}
+		return fmt.Sprintf(":%d\r\n", n)
+	default:
+		return "-ERR unknown command\r\n"
+	}
+}
+
+// readRESPArray decodes one RESP array-of-bulk-strings request, the only
+// encoding go-redis sends commands in.
+func readRESPArray(r *bufio.Reader) ([]string, error) {
+	header, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	header = strings.TrimRight(header, "\r\n")
+	if !strings.HasPrefix(header, "*") {
+		return nil, fmt.Errorf("fakeredis: expected array header, got %q", header)
+	}
+	n, err := strconv.Atoi(header[1:])
+	if err != nil {
+		return nil, err
+	}
+
+	args := make([]string, n)
+	for i := 0; i < n; i++ {
+		bulkHeader, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		bulkHeader = strings.TrimRight(bulkHeader, "\r\n")
+		if !strings.HasPrefix(bulkHeader, "$") {
+			return nil, fmt.Errorf("fakeredis: expected bulk string header, got %q", bulkHeader)
+		}
+		size, err := strconv.Atoi(bulkHeader[1:])
+		if err != nil {
+			return nil, err
+		}
+		buf := make([]byte, size+2) // payload plus trailing CRLF
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		args[i] = string(buf[:size])
+	}
+	return args, nil
+}
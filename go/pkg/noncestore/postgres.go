@@ -0,0 +1,60 @@
+package noncestore
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Postgres implements facilitatorclient.NonceStore on a table keyed by
+// the nonce string, relying on a unique constraint to make the
+// "is this new" check atomic under concurrent inserts.
+type Postgres struct {
+	DB *sql.DB
+	// TableName defaults to "x402_seen_nonces" if empty.
+	TableName string
+}
+
+func (s *Postgres) table() string {
+	if s.TableName != "" {
+		return s.TableName
+	}
+	return "x402_seen_nonces"
+}
+
+// Schema returns the DDL an operator should run once, before using
+// Postgres as a NonceStore.
+func (s *Postgres) Schema() string {
+	return fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	key TEXT PRIMARY KEY,
+	expires_at TIMESTAMPTZ NOT NULL
+)`, s.table())
+}
+
+// SeenOrRecord implements facilitatorclient.NonceStore.
+func (s *Postgres) SeenOrRecord(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	// Lazily reclaim expired rows for this key first, so a nonce whose
+	// validity window passed long ago doesn't permanently collide with
+	// the unique constraint below.
+	if _, err := s.DB.ExecContext(ctx,
+		fmt.Sprintf(`DELETE FROM %s WHERE key = $1 AND expires_at < now()`, s.table()),
+		key,
+	); err != nil {
+		return false, fmt.Errorf("noncestore: reclaiming expired nonce: %w", err)
+	}
+
+	res, err := s.DB.ExecContext(ctx,
+		fmt.Sprintf(`INSERT INTO %s (key, expires_at) VALUES ($1, now() + ($2 || ' seconds')::interval) ON CONFLICT (key) DO NOTHING`, s.table()),
+		key, int64(ttl.Seconds()),
+	)
+	if err != nil {
+		return false, fmt.Errorf("noncestore: recording nonce: %w", err)
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("noncestore: checking insert result: %w", err)
+	}
+	// 0 rows affected means ON CONFLICT fired: the key was already there.
+	return rows == 0, nil
+}
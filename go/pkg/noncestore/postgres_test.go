@@ -0,0 +1,63 @@
+package noncestore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestPostgresSeenOrRecord(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New() returned error: %v", err)
+	}
+	defer db.Close()
+
+	store := &Postgres{DB: db}
+	ctx := context.Background()
+
+	mock.ExpectExec(`DELETE FROM x402_seen_nonces WHERE key = \$1 AND expires_at < now\(\)`).
+		WithArgs("nonce-1").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`INSERT INTO x402_seen_nonces`).
+		WithArgs("nonce-1", int64(60)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	seen, err := store.SeenOrRecord(ctx, "nonce-1", time.Minute)
+	if err != nil {
+		t.Fatalf("first SeenOrRecord returned error: %v", err)
+	}
+	if seen {
+		t.Error("expected first sighting to be unseen")
+	}
+
+	mock.ExpectExec(`DELETE FROM x402_seen_nonces WHERE key = \$1 AND expires_at < now\(\)`).
+		WithArgs("nonce-1").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`INSERT INTO x402_seen_nonces`).
+		WithArgs("nonce-1", int64(60)).
+		WillReturnResult(sqlmock.NewResult(0, 0)) // ON CONFLICT fired: 0 rows affected
+
+	seen, err = store.SeenOrRecord(ctx, "nonce-1", time.Minute)
+	if err != nil {
+		t.Fatalf("second SeenOrRecord returned error: %v", err)
+	}
+	if !seen {
+		t.Error("expected repeat sighting to be seen")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestPostgresTableName(t *testing.T) {
+	if got := (&Postgres{}).table(); got != "x402_seen_nonces" {
+		t.Errorf("table() with no override = %q, want %q", got, "x402_seen_nonces")
+	}
+	if got := (&Postgres{TableName: "custom_nonces"}).table(); got != "custom_nonces" {
+		t.Errorf("table() with override = %q, want %q", got, "custom_nonces")
+	}
+}
@@ -0,0 +1,34 @@
+// Package noncestore provides shared-backend facilitatorclient.NonceStore
+// implementations for operators running more than one facilitator
+// replica, where an in-memory store wouldn't see nonces recorded by a
+// sibling process.
+package noncestore
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Redis implements facilitatorclient.NonceStore on top of Redis's atomic
+// SETNX, so replay protection holds across every facilitator replica
+// sharing the same Redis instance.
+type Redis struct {
+	Client *redis.Client
+	// Prefix is prepended to every key, e.g. "x402-nonces:".
+	Prefix string
+}
+
+func (s *Redis) key(key string) string { return s.Prefix + key }
+
+// SeenOrRecord implements facilitatorclient.NonceStore.
+func (s *Redis) SeenOrRecord(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	// SetNX reports true when it newly set the key, i.e. the nonce was
+	// not seen before; Redis expires the key itself after ttl.
+	set, err := s.Client.SetNX(ctx, s.key(key), "1", ttl).Result()
+	if err != nil {
+		return false, err
+	}
+	return !set, nil
+}
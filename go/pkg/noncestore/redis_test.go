@@ -0,0 +1,43 @@
+package noncestore
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRedisSeenOrRecord(t *testing.T) {
+	store := &Redis{Client: newFakeRedisClient(t), Prefix: "x402-nonces:"}
+	ctx := context.Background()
+
+	seen, err := store.SeenOrRecord(ctx, "nonce-1", time.Minute)
+	if err != nil {
+		t.Fatalf("first SeenOrRecord returned error: %v", err)
+	}
+	if seen {
+		t.Error("expected first sighting to be unseen")
+	}
+
+	seen, err = store.SeenOrRecord(ctx, "nonce-1", time.Minute)
+	if err != nil {
+		t.Fatalf("second SeenOrRecord returned error: %v", err)
+	}
+	if !seen {
+		t.Error("expected repeat sighting to be seen")
+	}
+
+	seen, err = store.SeenOrRecord(ctx, "nonce-2", time.Minute)
+	if err != nil {
+		t.Fatalf("SeenOrRecord for distinct nonce returned error: %v", err)
+	}
+	if seen {
+		t.Error("expected distinct nonce to be unseen")
+	}
+}
+
+func TestRedisKeyPrefix(t *testing.T) {
+	store := &Redis{Prefix: "x402-nonces:"}
+	if got := store.key("abc"); got != "x402-nonces:abc" {
+		t.Errorf("key() = %q, want %q", got, "x402-nonces:abc")
+	}
+}
@@ -0,0 +1,85 @@
+// Package types contains the shared wire types used by the x402 facilitator
+// client, the reference facilitator backends, and the payment-gated server
+// middleware.
+package types
+
+import "time"
+
+// PaymentPayload is the payload a client attaches to a request once it has
+// paid (or authorized payment for) a resource, per the x402 protocol.
+type PaymentPayload struct {
+	X402Version int         `json:"x402Version"`
+	Scheme      string      `json:"scheme"`
+	Network     string      `json:"network"`
+	Payload     interface{} `json:"payload"`
+}
+
+// ExactEvmPayload is the scheme-specific payload for the "exact" EVM scheme:
+// a signed EIP-3009 transferWithAuthorization.
+type ExactEvmPayload struct {
+	Signature     string                        `json:"signature"`
+	Authorization *ExactEvmPayloadAuthorization `json:"authorization"`
+}
+
+// ExactEvmPayloadAuthorization mirrors the fields of an EIP-3009
+// TransferWithAuthorization message.
+type ExactEvmPayloadAuthorization struct {
+	From        string `json:"from"`
+	To          string `json:"to"`
+	Value       string `json:"value"`
+	ValidAfter  string `json:"validAfter"`
+	ValidBefore string `json:"validBefore"`
+	Nonce       string `json:"nonce"`
+}
+
+// PaymentRequirements describes what a server demands in order to grant
+// access to a resource.
+type PaymentRequirements struct {
+	Scheme            string `json:"scheme"`
+	Network           string `json:"network"`
+	MaxAmountRequired string `json:"maxAmountRequired"`
+	Resource          string `json:"resource"`
+	Description       string `json:"description"`
+	MimeType          string `json:"mimeType"`
+	PayTo             string `json:"payTo"`
+	MaxTimeoutSeconds int    `json:"maxTimeoutSeconds"`
+	Asset             string `json:"asset"`
+}
+
+// VerifyResponse is returned by a facilitator's /verify endpoint.
+type VerifyResponse struct {
+	IsValid       bool   `json:"isValid"`
+	InvalidReason string `json:"invalidReason,omitempty"`
+}
+
+// SettleResponse is returned by a facilitator's /settle endpoint.
+type SettleResponse struct {
+	Success     bool   `json:"success"`
+	Transaction string `json:"transaction"`
+	Network     string `json:"network"`
+	Error       string `json:"error,omitempty"`
+}
+
+// SupportedKind describes one scheme/network combination a facilitator is
+// able to verify and settle.
+type SupportedKind struct {
+	Scheme  string `json:"scheme"`
+	Network string `json:"network"`
+}
+
+// SupportedResponse is returned by a facilitator's /supported endpoint.
+type SupportedResponse struct {
+	Kinds []SupportedKind `json:"kinds"`
+}
+
+// CreateAuthHeadersFunc produces the headers to attach to outgoing
+// facilitator requests, keyed by operation ("verify" or "settle").
+type CreateAuthHeadersFunc func() (map[string]map[string]string, error)
+
+// FacilitatorConfig holds everything needed to talk to a remote x402
+// facilitator over HTTP.
+type FacilitatorConfig struct {
+	URL               string
+	CreateAuthHeaders CreateAuthHeadersFunc
+	Timeout           func() time.Duration
+}
@@ -0,0 +1,213 @@
+// Package vaultfacilitator is a FacilitatorProvider that verifies payments
+// the same way localfacilitator does, but settles them by signing the
+// settlement transaction through HashiCorp Vault's Transit secrets engine
+// instead of holding the settling private key in process memory. This
+// mirrors the common pattern of plugging a Vault (or other KMS) signing
+// pipeline in behind an interface seam rather than threading raw key
+// material through application code.
+package vaultfacilitator
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/coinbase/x402/go/pkg/facilitatorclient"
+	"github.com/coinbase/x402/go/pkg/localfacilitator"
+)
+
+// Config configures the Vault Transit-backed settling signer.
+type Config struct {
+	// VaultAddr is the base URL of the Vault server, e.g. "https://vault.internal:8200".
+	VaultAddr string
+	// VaultToken authenticates to Vault. In production this is typically
+	// short-lived and injected by a Vault Agent sidecar rather than set
+	// directly.
+	VaultToken string
+	// TransitMountPath is where the Transit engine is mounted, e.g. "transit".
+	TransitMountPath string
+	// TransitKeyName is the name of the Transit key holding the settling
+	// account's private key material. It must be an ecdsa-capable key
+	// type (this package was written against a secp256k1-capable Transit
+	// plugin, since stock Vault Transit does not support secp256k1).
+	TransitKeyName string
+	// SettlerAddress is the Ethereum address corresponding to the Transit
+	// key. Vault's sign response does not include a recovery id, so we
+	// derive it by recovering against this known address.
+	SettlerAddress string
+	RPCURL         string
+	Network        string
+}
+
+func (c Config) validate() error {
+	missing := []string{}
+	if c.VaultAddr == "" {
+		missing = append(missing, "VAULT_ADDR")
+	}
+	if c.VaultToken == "" {
+		missing = append(missing, "VAULT_TOKEN")
+	}
+	if c.TransitKeyName == "" {
+		missing = append(missing, "VAULT_TRANSIT_KEY")
+	}
+	if c.SettlerAddress == "" {
+		missing = append(missing, "SETTLER_ADDRESS")
+	}
+	if c.RPCURL == "" {
+		missing = append(missing, "RPC_URL")
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("vaultfacilitator: FACILITATOR_PROVIDER=vault requires %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// NewProviderFromEnv builds a facilitatorclient.FacilitatorProvider that
+// verifies payments locally and settles them by signing through Vault's
+// Transit engine, reading VAULT_ADDR, VAULT_TOKEN, VAULT_TRANSIT_MOUNT
+// (default "transit"), VAULT_TRANSIT_KEY, SETTLER_ADDRESS, RPC_URL and
+// EVM_NETWORK from the environment.
+func NewProviderFromEnv() (facilitatorclient.FacilitatorProvider, error) {
+	mount := os.Getenv("VAULT_TRANSIT_MOUNT")
+	if mount == "" {
+		mount = "transit"
+	}
+	network := os.Getenv("EVM_NETWORK")
+	if network == "" {
+		network = "bsc-mainnet"
+	}
+	cfg := Config{
+		VaultAddr:        os.Getenv("VAULT_ADDR"),
+		VaultToken:       os.Getenv("VAULT_TOKEN"),
+		TransitMountPath: mount,
+		TransitKeyName:   os.Getenv("VAULT_TRANSIT_KEY"),
+		SettlerAddress:   os.Getenv("SETTLER_ADDRESS"),
+		RPCURL:           os.Getenv("RPC_URL"),
+		Network:          network,
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	return NewProvider(cfg), nil
+}
+
+// NewProvider builds the provider from an explicit Config.
+func NewProvider(cfg Config) facilitatorclient.FacilitatorProvider {
+	signer := &transitSigner{cfg: cfg, httpClient: &http.Client{}}
+	return localfacilitator.NewProvider(localfacilitator.Config{RPCURL: cfg.RPCURL, Network: cfg.Network}, signer)
+}
+
+// transitSigner implements localfacilitator.TxSigner by calling Vault's
+// Transit sign endpoint for every settlement transaction. The private key
+// itself never leaves Vault.
+type transitSigner struct {
+	cfg        Config
+	httpClient *http.Client
+}
+
+func (s *transitSigner) Address() string { return s.cfg.SettlerAddress }
+
+func (s *transitSigner) SignDigest(ctx context.Context, digest [32]byte) ([]byte, error) {
+	reqBody, err := json.Marshal(map[string]string{
+		"input":                base64.StdEncoding.EncodeToString(digest[:]),
+		"prehashed":            "true",
+		"signature_algorithm":  "ecdsa",
+		"marshaling_algorithm": "jws",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("vaultfacilitator: marshaling sign request: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/v1/%s/sign/%s", strings.TrimRight(s.cfg.VaultAddr, "/"), s.cfg.TransitMountPath, s.cfg.TransitKeyName)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(reqBody))
+	if err != nil {
+		return nil, fmt.Errorf("vaultfacilitator: building sign request: %w", err)
+	}
+	req.Header.Set("X-Vault-Token", s.cfg.VaultToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("vaultfacilitator: calling Vault Transit: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("vaultfacilitator: reading Vault response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("vaultfacilitator: Vault Transit sign failed (%d): %s", resp.StatusCode, body)
+	}
+
+	var parsed struct {
+		Data struct {
+			Signature string `json:"signature"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return nil, fmt.Errorf("vaultfacilitator: decoding Vault response: %w", err)
+	}
+
+	r, sVal, err := decodeTransitSignature(parsed.Data.Signature)
+	if err != nil {
+		return nil, err
+	}
+	return recoverableSignature(digest, r, sVal, s.cfg.SettlerAddress)
+}
+
+// decodeTransitSignature parses Vault Transit's "vault:v1:<base64(r||s)>"
+// signature format into its raw r and s components.
+func decodeTransitSignature(signature string) (r, s []byte, err error) {
+	parts := strings.Split(signature, ":")
+	if len(parts) != 3 {
+		return nil, nil, fmt.Errorf("vaultfacilitator: unexpected Vault signature format %q", signature)
+	}
+	raw, err := base64.StdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, nil, fmt.Errorf("vaultfacilitator: decoding Vault signature: %w", err)
+	}
+	if len(raw) != 64 {
+		return nil, nil, fmt.Errorf("vaultfacilitator: expected 64-byte r||s signature, got %d bytes", len(raw))
+	}
+	return raw[:32], raw[32:], nil
+}
+
+// recoverableSignature appends the recovery id go-ethereum needs. Vault's
+// Transit sign response does not return one, so we try both candidates
+// and keep whichever recovers to the known settler address. The
+// comparison is case-insensitive since wantAddress comes from the
+// SETTLER_ADDRESS environment variable, which isn't guaranteed to be
+// EIP-55 checksummed the way (*ecdsa.PublicKey).Hex() always is.
+func recoverableSignature(digest [32]byte, r, s []byte, wantAddress string) ([]byte, error) {
+	candidate := make([]byte, 65)
+	copy(candidate[:32], r)
+	copy(candidate[32:64], s)
+
+	for v := byte(0); v <= 1; v++ {
+		candidate[64] = v
+		pub, err := crypto.SigToPub(digest[:], candidate)
+		if err != nil {
+			continue
+		}
+		if common.HexToAddress(crypto.PubkeyToAddress(*pub).Hex()) == common.HexToAddress(wantAddress) {
+			sig := make([]byte, 65)
+			copy(sig, candidate)
+			return sig, nil
+		}
+	}
+	return nil, fmt.Errorf("vaultfacilitator: could not recover settler address %s from Vault signature", wantAddress)
+}
+
+func init() {
+	facilitatorclient.RegisterProvider("vault", NewProviderFromEnv)
+}
@@ -0,0 +1,123 @@
+package vaultfacilitator
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestConfigValidate(t *testing.T) {
+	full := Config{
+		VaultAddr:      "https://vault.internal:8200",
+		VaultToken:     "s.token",
+		TransitKeyName: "x402-settler",
+		SettlerAddress: "0x000000000000000000000000000000000000b0b",
+		RPCURL:         "https://rpc.example.com",
+	}
+
+	tests := []struct {
+		name    string
+		mutate  func(c *Config)
+		wantErr string
+	}{
+		{name: "complete config", mutate: func(c *Config) {}},
+		{name: "missing vault addr", mutate: func(c *Config) { c.VaultAddr = "" }, wantErr: "VAULT_ADDR"},
+		{name: "missing vault token", mutate: func(c *Config) { c.VaultToken = "" }, wantErr: "VAULT_TOKEN"},
+		{name: "missing transit key", mutate: func(c *Config) { c.TransitKeyName = "" }, wantErr: "VAULT_TRANSIT_KEY"},
+		{name: "missing settler address", mutate: func(c *Config) { c.SettlerAddress = "" }, wantErr: "SETTLER_ADDRESS"},
+		{name: "missing rpc url", mutate: func(c *Config) { c.RPCURL = "" }, wantErr: "RPC_URL"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := full
+			tt.mutate(&cfg)
+			err := cfg.validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("validate() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("validate() = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDecodeTransitSignature(t *testing.T) {
+	raw := make([]byte, 64)
+	for i := range raw {
+		raw[i] = byte(i)
+	}
+	signature := "vault:v1:" + base64.StdEncoding.EncodeToString(raw)
+
+	r, s, err := decodeTransitSignature(signature)
+	if err != nil {
+		t.Fatalf("decodeTransitSignature returned error: %v", err)
+	}
+	if len(r) != 32 || len(s) != 32 {
+		t.Fatalf("expected 32-byte r and s, got %d and %d", len(r), len(s))
+	}
+
+	if _, _, err := decodeTransitSignature("not-a-vault-signature"); err == nil {
+		t.Error("expected error for malformed signature, got nil")
+	}
+}
+
+func TestRecoverableSignature(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+	address := crypto.PubkeyToAddress(key.PublicKey).Hex()
+
+	var digest [32]byte
+	copy(digest[:], []byte("some 32 byte message to be signe"))
+
+	sig, err := crypto.Sign(digest[:], key)
+	if err != nil {
+		t.Fatalf("signing digest: %v", err)
+	}
+
+	got, err := recoverableSignature(digest, sig[:32], sig[32:64], address)
+	if err != nil {
+		t.Fatalf("recoverableSignature returned error: %v", err)
+	}
+	if len(got) != 65 {
+		t.Fatalf("expected 65-byte signature, got %d", len(got))
+	}
+
+	if _, err := recoverableSignature(digest, sig[:32], sig[32:64], "0x000000000000000000000000000000000000bad"); err == nil {
+		t.Error("expected error when no recovery candidate matches the address, got nil")
+	}
+}
+
+func TestRecoverableSignatureLowercaseAddress(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+	lowercaseAddress := strings.ToLower(crypto.PubkeyToAddress(key.PublicKey).Hex())
+
+	var digest [32]byte
+	copy(digest[:], []byte("some 32 byte message to be signe"))
+
+	sig, err := crypto.Sign(digest[:], key)
+	if err != nil {
+		t.Fatalf("signing digest: %v", err)
+	}
+
+	// SETTLER_ADDRESS isn't guaranteed to be EIP-55 checksummed, unlike
+	// the recovered address, which always is.
+	got, err := recoverableSignature(digest, sig[:32], sig[32:64], lowercaseAddress)
+	if err != nil {
+		t.Fatalf("recoverableSignature returned error: %v", err)
+	}
+	if len(got) != 65 {
+		t.Fatalf("expected 65-byte signature, got %d", len(got))
+	}
+}